@@ -0,0 +1,18 @@
+package gothought
+
+import (
+	"context"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// Cache stores final model responses so that repeated queries can be answered
+// without calling the provider again.
+type Cache interface {
+	// Get looks up a cached response for the given conversation.
+	// The boolean result reports whether a response was found.
+	Get(ctx context.Context, tools map[string]tool.Tool, messages []Message) (*Message, bool, error)
+
+	// Set stores the final response produced for the given conversation.
+	Set(ctx context.Context, tools map[string]tool.Tool, messages []Message, response *Message) error
+}
@@ -0,0 +1,69 @@
+package gothought
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// CallOption configures a single query, as opposed to Option which configures the model.
+type CallOption func(c *callConfig)
+
+type callConfig struct {
+	// tools are the names of the tools and toolkits offered, nil offers all tools
+	tools []string
+	// toolChoice returns the tool choice of an iteration, nil is always auto
+	toolChoice        func(iteration, maxIterations int, called bool) ToolChoice
+	parallelToolCalls *bool
+}
+
+// WithTools offers only the named tools to the model for this call. A name may also
+// refer to a toolkit added with AddToolkit, selecting all of its tools.
+func WithTools(names ...string) CallOption {
+	return func(c *callConfig) {
+		if c.tools == nil {
+			c.tools = []string{}
+		}
+		c.tools = append(c.tools, names...)
+	}
+}
+
+func newCallConfig(options []CallOption) *callConfig {
+	cfg := &callConfig{}
+	for _, option := range options {
+		option(cfg)
+	}
+	return cfg
+}
+
+// toolsFor returns the tools offered to the model for a call: the subset requested with
+// WithTools, narrowed down by the ToolSelector of the model if one is configured.
+func (l *LanguageModel) toolsFor(ctx context.Context, messages []Message, cfg *callConfig) (map[string]tool.Tool, error) {
+	if len(l.conflicts) > 0 {
+		return nil, fmt.Errorf("%w: %q is already registered", ErrToolConflict, l.conflicts[0])
+	}
+
+	tools := l.tools
+	if cfg.tools != nil {
+		tools = make(map[string]tool.Tool, len(cfg.tools))
+		for _, name := range cfg.tools {
+			if t, ok := l.tools[name]; ok {
+				tools[name] = t
+				continue
+			}
+			members, ok := l.toolkits[name]
+			if !ok {
+				return nil, fmt.Errorf("%w: %q", ErrUnknownTool, name)
+			}
+			for _, member := range members {
+				tools[member] = l.tools[member]
+			}
+		}
+	}
+
+	if l.toolSelector != nil && len(tools) > 0 {
+		return l.toolSelector.SelectTools(ctx, tools, messages)
+	}
+	return tools, nil
+}
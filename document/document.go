@@ -0,0 +1,81 @@
+package document
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// Document is a unit of text together with metadata describing where it came from.
+// Loaders produce one Document per file, row or record; splitters turn them into
+// smaller chunks that keep the parent metadata.
+type Document struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// Loader reads documents from a source such as a file or a directory.
+type Loader interface {
+	Load(ctx context.Context) ([]Document, error)
+}
+
+// Splitter breaks documents into chunks small enough to embed and retrieve.
+type Splitter interface {
+	Split(docs []Document) ([]Document, error)
+}
+
+// Ingest embeds the documents with e and upserts them into store.
+// Documents are embedded in a single call so the embedder can batch them efficiently.
+func Ingest(ctx context.Context, e embedding.Embedder, store vectorstore.VectorStore, docs []Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+
+	texts := make([]string, len(docs))
+	for i, doc := range docs {
+		if doc.ID == "" {
+			return fmt.Errorf("document %d has no id", i)
+		}
+		texts[i] = doc.Content
+	}
+
+	vectors, err := e.Embed(ctx, texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(docs) {
+		return fmt.Errorf("embedder returned %d vectors for %d documents", len(vectors), len(docs))
+	}
+
+	records := make([]vectorstore.Document, len(docs))
+	for i, doc := range docs {
+		records[i] = vectorstore.Document{
+			ID:       doc.ID,
+			Content:  doc.Content,
+			Metadata: doc.Metadata,
+			Vector:   vectors[i],
+		}
+	}
+	return store.Upsert(ctx, records...)
+}
+
+// chunk derives the i-th chunk of parent, copying its metadata.
+func chunk(parent Document, i int, content string, extra map[string]any) Document {
+	metadata := make(map[string]any, len(parent.Metadata)+len(extra)+1)
+	for k, v := range parent.Metadata {
+		metadata[k] = v
+	}
+	for k, v := range extra {
+		metadata[k] = v
+	}
+	metadata["chunk"] = i
+
+	return Document{
+		ID:       fmt.Sprintf("%s#%d", parent.ID, i),
+		Content:  content,
+		Metadata: metadata,
+	}
+}
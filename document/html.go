@@ -0,0 +1,111 @@
+package document
+
+import (
+	"html"
+	"strings"
+)
+
+// blockTags are elements that start a new line in the extracted text.
+var blockTags = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "br": true,
+	"dd": true, "div": true, "dl": true, "dt": true, "figcaption": true, "footer": true,
+	"form": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "li": true, "main": true, "nav": true, "ol": true,
+	"p": true, "pre": true, "section": true, "table": true, "td": true, "th": true,
+	"tr": true, "ul": true,
+}
+
+// skipTags are elements whose content is never part of the extracted text.
+var skipTags = map[string]bool{
+	"head": true, "noscript": true, "script": true, "style": true, "svg": true, "template": true,
+}
+
+// ExtractHTML returns the visible text and the <title> of an HTML document.
+// Block-level elements are separated by newlines, scripts and styles are dropped
+// and character references are decoded.
+func ExtractHTML(src string) (text string, title string) {
+	var (
+		out     strings.Builder
+		titleSb strings.Builder
+		skip    string
+		inTitle bool
+	)
+
+	for len(src) > 0 {
+		lt := strings.IndexByte(src, '<')
+		if lt < 0 {
+			lt = len(src)
+		}
+
+		if lt > 0 {
+			segment := src[:lt]
+			switch {
+			case inTitle:
+				titleSb.WriteString(segment)
+			case skip == "":
+				out.WriteString(segment)
+			}
+			src = src[lt:]
+			continue
+		}
+
+		if strings.HasPrefix(src, "<!--") {
+			end := strings.Index(src, "-->")
+			if end < 0 {
+				break
+			}
+			src = src[end+3:]
+			continue
+		}
+
+		gt := strings.IndexByte(src, '>')
+		if gt < 0 {
+			break
+		}
+		tag := src[1:gt]
+		src = src[gt+1:]
+
+		closing := strings.HasPrefix(tag, "/")
+		name := strings.ToLower(strings.TrimLeft(tag, "/!"))
+		if i := strings.IndexAny(name, " \t\r\n/"); i >= 0 {
+			name = name[:i]
+		}
+
+		switch {
+		case name == "title":
+			inTitle = !closing
+		case skip != "":
+			if closing && name == skip {
+				skip = ""
+			}
+		case skipTags[name] && !closing && !strings.HasSuffix(tag, "/"):
+			skip = name
+		case blockTags[name]:
+			out.WriteByte('\n')
+		}
+	}
+
+	return normalizeText(html.UnescapeString(out.String())), strings.TrimSpace(html.UnescapeString(titleSb.String()))
+}
+
+// normalizeText collapses runs of spaces within lines and drops blank lines beyond a single paragraph break.
+func normalizeText(s string) string {
+	lines := strings.Split(s, "\n")
+	var (
+		out   []string
+		blank bool
+	)
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			blank = len(out) > 0
+			continue
+		}
+		if blank {
+			out = append(out, "")
+			blank = false
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
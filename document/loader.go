@@ -0,0 +1,302 @@
+package document
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// TextLoader loads a plain text file as a single document.
+type TextLoader struct {
+	path string
+}
+
+// NewTextLoader creates a new instance of TextLoader
+func NewTextLoader(path string) *TextLoader {
+	return &TextLoader{path: path}
+}
+
+// Load reads the file and returns it as one document.
+func (t *TextLoader) Load(ctx context.Context) ([]Document, error) {
+	bt, err := os.ReadFile(t.path)
+	if err != nil {
+		return nil, err
+	}
+	return []Document{{
+		ID:       t.path,
+		Content:  string(bt),
+		Metadata: map[string]any{"source": t.path, "format": "text"},
+	}}, nil
+}
+
+// MarkdownLoader loads a Markdown file as a single document.
+// The first level-one heading, if any, is recorded as the "title" metadata.
+type MarkdownLoader struct {
+	path string
+}
+
+// NewMarkdownLoader creates a new instance of MarkdownLoader
+func NewMarkdownLoader(path string) *MarkdownLoader {
+	return &MarkdownLoader{path: path}
+}
+
+// Load reads the file and returns it as one document.
+func (m *MarkdownLoader) Load(ctx context.Context) ([]Document, error) {
+	bt, err := os.ReadFile(m.path)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]any{"source": m.path, "format": "markdown"}
+	for _, line := range strings.Split(string(bt), "\n") {
+		if title, ok := strings.CutPrefix(line, "# "); ok {
+			metadata["title"] = strings.TrimSpace(title)
+			break
+		}
+	}
+
+	return []Document{{ID: m.path, Content: string(bt), Metadata: metadata}}, nil
+}
+
+// HTMLLoader loads an HTML file and keeps only its visible text.
+type HTMLLoader struct {
+	path string
+}
+
+// NewHTMLLoader creates a new instance of HTMLLoader
+func NewHTMLLoader(path string) *HTMLLoader {
+	return &HTMLLoader{path: path}
+}
+
+// Load reads the file, extracts its text and returns it as one document.
+func (h *HTMLLoader) Load(ctx context.Context) ([]Document, error) {
+	bt, err := os.ReadFile(h.path)
+	if err != nil {
+		return nil, err
+	}
+
+	text, title := ExtractHTML(string(bt))
+	metadata := map[string]any{"source": h.path, "format": "html"}
+	if title != "" {
+		metadata["title"] = title
+	}
+	return []Document{{ID: h.path, Content: text, Metadata: metadata}}, nil
+}
+
+// CSVLoader loads a CSV file with a header row, producing one document per record.
+// The content of each document lists the record as "column: value" lines, and every
+// column is also available as metadata.
+type CSVLoader struct {
+	path          string
+	contentColumn string
+}
+
+// NewCSVLoader creates a new instance of CSVLoader.
+// If contentColumn is not empty, only that column is used as the document content.
+func NewCSVLoader(path string, contentColumn string) *CSVLoader {
+	return &CSVLoader{path: path, contentColumn: contentColumn}
+}
+
+// Load reads every record of the file.
+func (c *CSVLoader) Load(ctx context.Context) ([]Document, error) {
+	f, err := os.Open(c.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if c.contentColumn != "" && !lo.Contains(header, c.contentColumn) {
+		return nil, fmt.Errorf("column %q not found in %s", c.contentColumn, c.path)
+	}
+
+	var docs []Document
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		metadata := map[string]any{"source": c.path, "format": "csv", "row": row}
+		var content strings.Builder
+		// encoding/csv rejects records with a different number of fields than the header
+		for i, column := range header {
+			metadata[column] = record[i]
+
+			switch {
+			case c.contentColumn == column:
+				content.WriteString(record[i])
+			case c.contentColumn == "":
+				content.WriteString(fmt.Sprintf("%s: %s\n", column, record[i]))
+			}
+		}
+
+		docs = append(docs, Document{
+			ID:       fmt.Sprintf("%s:%d", c.path, row),
+			Content:  strings.TrimSpace(content.String()),
+			Metadata: metadata,
+		})
+	}
+	return docs, nil
+}
+
+// JSONLinesLoader loads a JSON Lines file, producing one document per object.
+// The value under contentKey becomes the content and every other key becomes metadata,
+// with numbers kept as json.Number. A string or number under "id" becomes the document ID.
+type JSONLinesLoader struct {
+	path       string
+	contentKey string
+}
+
+// NewJSONLinesLoader creates a new instance of JSONLinesLoader
+func NewJSONLinesLoader(path string, contentKey string) *JSONLinesLoader {
+	return &JSONLinesLoader{path: path, contentKey: contentKey}
+}
+
+// Load reads every line of the file.
+func (j *JSONLinesLoader) Load(ctx context.Context) ([]Document, error) {
+	f, err := os.Open(j.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var docs []Document
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record map[string]any
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", j.path, line, err)
+		}
+		if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%s:%d: unexpected data after the object", j.path, line)
+		}
+
+		content, ok := record[j.contentKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: missing string field %q", j.path, line, j.contentKey)
+		}
+
+		metadata := map[string]any{"source": j.path, "format": "jsonl", "line": line}
+		for k, v := range record {
+			if k != j.contentKey {
+				metadata[k] = v
+			}
+		}
+
+		id := fmt.Sprintf("%s:%d", j.path, line)
+		if v, ok := record["id"]; ok {
+			switch v := v.(type) {
+			case string:
+				id = v
+			case json.Number:
+				id = v.String()
+			default:
+				return nil, fmt.Errorf("%s:%d: id must be a string or a number", j.path, line)
+			}
+		}
+		docs = append(docs, Document{ID: id, Content: content, Metadata: metadata})
+	}
+	return docs, scanner.Err()
+}
+
+// DirectoryLoader walks a directory and loads every file with a known extension.
+// Files are dispatched by extension: .txt, .md/.markdown, .html/.htm, .csv and .jsonl.
+// CSV files use every column as content and JSON Lines files use the "text" key.
+type DirectoryLoader struct {
+	root    string
+	loaders map[string]func(path string) Loader
+}
+
+// NewDirectoryLoader creates a new instance of DirectoryLoader
+func NewDirectoryLoader(root string) *DirectoryLoader {
+	return &DirectoryLoader{
+		root: root,
+		loaders: map[string]func(path string) Loader{
+			".txt":      func(path string) Loader { return NewTextLoader(path) },
+			".md":       func(path string) Loader { return NewMarkdownLoader(path) },
+			".markdown": func(path string) Loader { return NewMarkdownLoader(path) },
+			".html":     func(path string) Loader { return NewHTMLLoader(path) },
+			".htm":      func(path string) Loader { return NewHTMLLoader(path) },
+			".csv":      func(path string) Loader { return NewCSVLoader(path, "") },
+			".jsonl":    func(path string) Loader { return NewJSONLinesLoader(path, "text") },
+		},
+	}
+}
+
+// WithLoader registers or replaces the loader used for files with the given extension.
+func (d *DirectoryLoader) WithLoader(ext string, loader func(path string) Loader) *DirectoryLoader {
+	d.loaders[strings.ToLower(ext)] = loader
+	return d
+}
+
+// Load walks the directory in lexical order and loads every supported file.
+// Hidden files and directories are skipped.
+func (d *DirectoryLoader) Load(ctx context.Context) ([]Document, error) {
+	var paths []string
+	err := filepath.WalkDir(d.root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path != d.root && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.IsDir() {
+			if _, ok := d.loaders[strings.ToLower(filepath.Ext(path))]; ok {
+				paths = append(paths, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	var docs []Document
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		loaded, err := d.loaders[strings.ToLower(filepath.Ext(path))](path).Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, loaded...)
+	}
+	return docs, nil
+}
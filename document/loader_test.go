@@ -0,0 +1,64 @@
+package document
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryLoader_Load(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":         "plain text",
+		"b.md":          "# Title\n\nbody",
+		"c.html":        "<html><head><title>Page &amp; Co</title><style>p{}</style></head><body><h1>Hello</h1><p>World <b>wide</b></p><script>x()</script></body></html>",
+		"d.csv":         "name,role\nada,engineer\ngrace,admiral\n",
+		"e.jsonl":       "{\"id\": \"r1\", \"text\": \"first\", \"tag\": \"x\"}\n\n{\"text\": \"second\"}\n",
+		"skip.bin":      "ignored",
+		".hidden/f.txt": "ignored",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+
+	docs, err := NewDirectoryLoader(dir).Load(context.TODO())
+	require.NoError(t, err)
+	require.Len(t, docs, 7)
+
+	require.Equal(t, "plain text", docs[0].Content)
+	require.Equal(t, "Title", docs[1].Metadata["title"])
+
+	require.Equal(t, "Hello\n\nWorld wide", docs[2].Content)
+	require.Equal(t, "Page & Co", docs[2].Metadata["title"])
+
+	require.Equal(t, "name: ada\nrole: engineer", docs[3].Content)
+	require.Equal(t, "admiral", docs[4].Metadata["role"])
+
+	require.Equal(t, "r1", docs[5].ID)
+	require.Equal(t, "x", docs[5].Metadata["tag"])
+	require.Equal(t, "second", docs[6].Content)
+	require.Equal(t, 3, docs[6].Metadata["line"])
+}
+
+func TestJSONLinesLoader_IDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "docs.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte(
+		"{\"id\": 1000000, \"text\": \"a\", \"score\": 0.5}\n"+
+			"{\"id\": 12345678901234567890, \"text\": \"b\"}\n"+
+			"{\"id\": \"c\", \"text\": \"c\"}\n"), 0o644))
+
+	docs, err := NewJSONLinesLoader(path, "text").Load(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, []string{"1000000", "12345678901234567890", "c"}, []string{docs[0].ID, docs[1].ID, docs[2].ID})
+	require.Equal(t, json.Number("0.5"), docs[0].Metadata["score"])
+
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\": {\"a\": 1}, \"text\": \"a\"}\n"), 0o644))
+	_, err = NewJSONLinesLoader(path, "text").Load(context.TODO())
+	require.ErrorContains(t, err, "id must be a string or a number")
+}
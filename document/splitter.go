@@ -0,0 +1,258 @@
+package document
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// RecursiveCharacterSplitter splits text on a list of separators, trying the coarsest
+// first (paragraphs, then lines, then words) and only falling back to finer ones for
+// pieces that are still too long. Neighbouring pieces are merged back together up to
+// ChunkSize characters, and consecutive chunks share up to ChunkOverlap characters.
+type RecursiveCharacterSplitter struct {
+	ChunkSize    int
+	ChunkOverlap int
+	Separators   []string
+}
+
+// NewRecursiveCharacterSplitter creates a splitter with the default separators.
+func NewRecursiveCharacterSplitter(chunkSize, chunkOverlap int) *RecursiveCharacterSplitter {
+	return &RecursiveCharacterSplitter{
+		ChunkSize:    chunkSize,
+		ChunkOverlap: chunkOverlap,
+		Separators:   []string{"\n\n", "\n", " ", ""},
+	}
+}
+
+// Split breaks every document into chunks.
+func (r *RecursiveCharacterSplitter) Split(docs []Document) ([]Document, error) {
+	var out []Document
+	for _, doc := range docs {
+		for i, text := range r.SplitText(doc.Content) {
+			out = append(out, chunk(doc, i, text, nil))
+		}
+	}
+	return out, nil
+}
+
+// SplitText breaks text into chunks of at most ChunkSize characters where possible.
+func (r *RecursiveCharacterSplitter) SplitText(text string) []string {
+	return r.split(text, r.Separators)
+}
+
+func (r *RecursiveCharacterSplitter) split(text string, separators []string) []string {
+	separator := ""
+	var rest []string
+	for i, sep := range separators {
+		if sep == "" || strings.Contains(text, sep) {
+			separator = sep
+			rest = separators[i+1:]
+			break
+		}
+	}
+
+	var pieces []string
+	if separator == "" {
+		pieces = strings.Split(text, "")
+	} else {
+		pieces = strings.Split(text, separator)
+	}
+
+	var (
+		chunks []string
+		good   []string
+	)
+	for _, piece := range pieces {
+		if piece == "" {
+			continue
+		}
+		if utf8.RuneCountInString(piece) <= r.ChunkSize {
+			good = append(good, piece)
+			continue
+		}
+
+		chunks = append(chunks, merge(good, separator, r.ChunkSize, r.ChunkOverlap)...)
+		good = nil
+		if len(rest) == 0 {
+			chunks = append(chunks, piece)
+		} else {
+			chunks = append(chunks, r.split(piece, rest)...)
+		}
+	}
+	return append(chunks, merge(good, separator, r.ChunkSize, r.ChunkOverlap)...)
+}
+
+// merge joins pieces with separator into chunks no longer than size, carrying up to
+// overlap characters of trailing pieces into the next chunk.
+func merge(pieces []string, separator string, size, overlap int) []string {
+	sepLen := utf8.RuneCountInString(separator)
+
+	var (
+		chunks  []string
+		current []string
+		total   int
+	)
+	joinedLen := func(n int) int {
+		if len(current) == 0 {
+			return n
+		}
+		return total + sepLen + n
+	}
+
+	for _, piece := range pieces {
+		n := utf8.RuneCountInString(piece)
+		if len(current) > 0 && joinedLen(n) > size {
+			if text := strings.TrimSpace(strings.Join(current, separator)); text != "" {
+				chunks = append(chunks, text)
+			}
+			for len(current) > 0 && (total > overlap || joinedLen(n) > size) {
+				total -= utf8.RuneCountInString(current[0])
+				if len(current) > 1 {
+					total -= sepLen
+				}
+				current = current[1:]
+			}
+		}
+		total = joinedLen(n)
+		current = append(current, piece)
+	}
+
+	if text := strings.TrimSpace(strings.Join(current, separator)); text != "" {
+		chunks = append(chunks, text)
+	}
+	return chunks
+}
+
+var markdownHeader = regexp.MustCompile(`^(#{1,6})\s+(.+?)\s*#*\s*$`)
+
+// MarkdownHeaderSplitter splits Markdown at headings up to MaxLevel, so every chunk is
+// one section. The titles of the enclosing headings are recorded as "h1", "h2", ...
+// metadata, which keeps the document structure available for filtering and citation.
+// Headings inside fenced code blocks are ignored.
+type MarkdownHeaderSplitter struct {
+	MaxLevel int
+}
+
+// NewMarkdownHeaderSplitter creates a splitter that breaks on headings of level 1 to maxLevel.
+func NewMarkdownHeaderSplitter(maxLevel int) *MarkdownHeaderSplitter {
+	if maxLevel <= 0 || maxLevel > 6 {
+		maxLevel = 6
+	}
+	return &MarkdownHeaderSplitter{MaxLevel: maxLevel}
+}
+
+// Split breaks every document into sections.
+func (m *MarkdownHeaderSplitter) Split(docs []Document) ([]Document, error) {
+	var out []Document
+	for _, doc := range docs {
+		var (
+			headers = make([]string, 6)
+			section []string
+			meta    map[string]any
+			fenced  bool
+			index   int
+		)
+
+		flush := func() {
+			text := strings.TrimSpace(strings.Join(section, "\n"))
+			if text != "" {
+				out = append(out, chunk(doc, index, text, meta))
+				index++
+			}
+			section = nil
+		}
+		snapshot := func() map[string]any {
+			headerMeta := map[string]any{}
+			for i, h := range headers {
+				if h != "" {
+					headerMeta["h"+string(rune('1'+i))] = h
+				}
+			}
+			return headerMeta
+		}
+
+		meta = snapshot()
+		for _, line := range strings.Split(doc.Content, "\n") {
+			trimmed := strings.TrimSpace(line)
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fenced = !fenced
+			}
+
+			if match := markdownHeader.FindStringSubmatch(line); !fenced && match != nil && len(match[1]) <= m.MaxLevel {
+				flush()
+				level := len(match[1])
+				headers[level-1] = match[2]
+				for i := level; i < len(headers); i++ {
+					headers[i] = ""
+				}
+				meta = snapshot()
+			}
+			section = append(section, line)
+		}
+		flush()
+	}
+	return out, nil
+}
+
+// Tokenizer splits text into tokens that concatenate back to the original text.
+type Tokenizer func(text string) []string
+
+var wordToken = regexp.MustCompile(`\s*\S+\s*`)
+
+// WordTokenizer treats every whitespace-separated word, including its surrounding
+// whitespace, as one token. It is a rough stand-in for model tokenizers.
+func WordTokenizer(text string) []string {
+	return wordToken.FindAllString(text, -1)
+}
+
+// TokenSplitter splits text into windows of ChunkSize tokens, each starting
+// ChunkSize-ChunkOverlap tokens after the previous one.
+type TokenSplitter struct {
+	ChunkSize    int
+	ChunkOverlap int
+	Tokenizer    Tokenizer
+}
+
+// NewTokenSplitter creates a TokenSplitter using WordTokenizer.
+func NewTokenSplitter(chunkSize, chunkOverlap int) *TokenSplitter {
+	return &TokenSplitter{ChunkSize: chunkSize, ChunkOverlap: chunkOverlap, Tokenizer: WordTokenizer}
+}
+
+// Split breaks every document into token windows.
+func (t *TokenSplitter) Split(docs []Document) ([]Document, error) {
+	var out []Document
+	for _, doc := range docs {
+		for i, text := range t.SplitText(doc.Content) {
+			out = append(out, chunk(doc, i, text, nil))
+		}
+	}
+	return out, nil
+}
+
+// SplitText breaks text into overlapping token windows.
+func (t *TokenSplitter) SplitText(text string) []string {
+	tokenize := t.Tokenizer
+	if tokenize == nil {
+		tokenize = WordTokenizer
+	}
+	tokens := tokenize(text)
+
+	step := t.ChunkSize - t.ChunkOverlap
+	if t.ChunkSize <= 0 || step <= 0 {
+		step = t.ChunkSize
+	}
+	if step <= 0 {
+		return []string{strings.TrimSpace(text)}
+	}
+
+	var chunks []string
+	for start := 0; start < len(tokens); start += step {
+		end := min(start+t.ChunkSize, len(tokens))
+		chunks = append(chunks, strings.TrimSpace(strings.Join(tokens[start:end], "")))
+		if end == len(tokens) {
+			break
+		}
+	}
+	return chunks
+}
@@ -0,0 +1,52 @@
+package document
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecursiveCharacterSplitter_SplitText(t *testing.T) {
+	text := "Go is expressive, concise, clean, and efficient.\n\nIts concurrency mechanisms make it easy to write programs.\nIt compiles quickly to machine code."
+	splitter := NewRecursiveCharacterSplitter(40, 10)
+
+	chunks := splitter.SplitText(text)
+	require.Greater(t, len(chunks), 2)
+	for _, c := range chunks {
+		require.LessOrEqual(t, utf8.RuneCountInString(c), 40, c)
+	}
+	require.Equal(t, "Go is expressive, concise, clean, and", chunks[0])
+	require.True(t, strings.HasPrefix(chunks[1], "clean, and"), "expected overlap, got %q", chunks[1])
+
+	require.Equal(t, []string{"short"}, splitter.SplitText("short"))
+}
+
+func TestMarkdownHeaderSplitter_Split(t *testing.T) {
+	doc := Document{ID: "guide.md", Content: "intro\n# Install\nrun go get\n## Linux\nuse apt\n```\n# not a header\n```\n# Usage\ncall Q", Metadata: map[string]any{"source": "guide.md"}}
+
+	chunks, err := NewMarkdownHeaderSplitter(2).Split([]Document{doc})
+	require.NoError(t, err)
+	require.Len(t, chunks, 4)
+
+	require.Equal(t, "intro", chunks[0].Content)
+	require.Equal(t, "guide.md#0", chunks[0].ID)
+
+	require.Equal(t, "Install", chunks[2].Metadata["h1"])
+	require.Equal(t, "Linux", chunks[2].Metadata["h2"])
+	require.Contains(t, chunks[2].Content, "# not a header")
+	require.Equal(t, "guide.md", chunks[2].Metadata["source"])
+
+	require.Equal(t, "Usage", chunks[3].Metadata["h1"])
+	require.NotContains(t, chunks[3].Metadata, "h2")
+}
+
+func TestTokenSplitter_SplitText(t *testing.T) {
+	splitter := NewTokenSplitter(4, 1)
+	require.Equal(t, []string{
+		"one two three four",
+		"four five six seven",
+		"seven eight",
+	}, splitter.SplitText("one two three four five six seven eight"))
+}
@@ -0,0 +1,59 @@
+package embedding
+
+import (
+	"context"
+	"math"
+)
+
+// Embedder converts text into dense vectors that can be compared by similarity.
+// Implementations must return exactly one vector per input text, in the same order.
+type Embedder interface {
+	// Embed returns the embedding vectors for the given texts.
+	// Implementations are responsible for splitting large inputs into batches
+	// that respect the limits of the underlying service.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+
+	// Dimensions returns the length of the vectors produced by Embed.
+	Dimensions() int
+
+	// Model returns the name of the embedding model.
+	Model() string
+}
+
+// Cosine returns the cosine similarity of a and b.
+// It returns 0 when the vectors differ in length or either of them has zero magnitude.
+func Cosine(a, b []float32) float32 {
+	if len(a) != len(b) {
+		return 0
+	}
+
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return float32(dot / (math.Sqrt(na) * math.Sqrt(nb)))
+}
+
+// Normalize scales v in place to unit length and returns it.
+// Zero vectors are returned unchanged.
+func Normalize(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return v
+	}
+
+	norm := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= norm
+	}
+	return v
+}
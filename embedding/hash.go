@@ -0,0 +1,75 @@
+package embedding
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// HashEmbedder is a deterministic, dependency-free Embedder based on feature hashing.
+// Each lower-cased word and adjacent word pair is hashed into one of a fixed number of
+// buckets, and the resulting vector is normalised to unit length. It captures lexical
+// overlap only, which makes it suitable for tests and offline development rather than
+// semantic retrieval.
+type HashEmbedder struct {
+	dimensions int
+}
+
+// NewHashEmbedder creates a new instance of HashEmbedder producing vectors of the given size.
+// Non-positive sizes default to 256 dimensions.
+func NewHashEmbedder(dimensions int) *HashEmbedder {
+	if dimensions <= 0 {
+		dimensions = 256
+	}
+	return &HashEmbedder{dimensions: dimensions}
+}
+
+// Embed returns one hashed vector per input text.
+func (h *HashEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		vectors[i] = h.embed(text)
+	}
+	return vectors, nil
+}
+
+// Dimensions returns the length of the produced vectors.
+func (h *HashEmbedder) Dimensions() int {
+	return h.dimensions
+}
+
+// Model returns the name of the embedder.
+func (h *HashEmbedder) Model() string {
+	return "hash"
+}
+
+func (h *HashEmbedder) embed(text string) []float32 {
+	vector := make([]float32, h.dimensions)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	for i, word := range words {
+		h.add(vector, word, 1)
+		if i > 0 {
+			h.add(vector, words[i-1]+" "+word, 0.5)
+		}
+	}
+	return Normalize(vector)
+}
+
+func (h *HashEmbedder) add(vector []float32, feature string, weight float32) {
+	hash := fnv.New64a()
+	hash.Write([]byte(feature))
+	sum := hash.Sum64()
+
+	// the high bit decides the sign so that collisions tend to cancel out
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vector[sum%uint64(len(vector))] += weight
+}
@@ -0,0 +1,27 @@
+package embedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashEmbedder_Embed(t *testing.T) {
+	e := NewHashEmbedder(64)
+	vectors, err := e.Embed(context.TODO(), []string{
+		"The quick brown fox",
+		"the quick brown fox!",
+		"Completely unrelated sentence about databases",
+	})
+	require.NoError(t, err)
+	require.Len(t, vectors, 3)
+	require.Len(t, vectors[0], 64)
+
+	require.InDelta(t, 1, Cosine(vectors[0], vectors[1]), 1e-6)
+	require.Less(t, Cosine(vectors[0], vectors[2]), float32(0.5))
+
+	again, err := e.Embed(context.TODO(), []string{"The quick brown fox"})
+	require.NoError(t, err)
+	require.Equal(t, vectors[0], again[0])
+}
@@ -0,0 +1,64 @@
+package jsonschema
+
+import "strings"
+
+// ApplyDefaults fills in the declared default of every missing object property in v,
+// a decoded JSON value, descending into nested objects and array items. It returns the
+// updated value; maps are modified in place.
+func (s *Schema) ApplyDefaults(v any) any {
+	return s.applyDefaults(s, v, 0)
+}
+
+func (s *Schema) applyDefaults(schema *Schema, v any, depth int) any {
+	if schema == nil || depth > 64 {
+		return v
+	}
+
+	if schema.Ref != "" {
+		if name, ok := strings.CutPrefix(schema.Ref, "#/$defs/"); ok {
+			return s.applyDefaults(s.Defs[name], v, depth+1)
+		}
+		if schema.Ref == "#" {
+			return s.applyDefaults(s, v, depth+1)
+		}
+	}
+	for _, sub := range schema.AllOf {
+		v = s.applyDefaults(sub, v, depth+1)
+	}
+
+	switch x := v.(type) {
+	case map[string]any:
+		for _, name := range schema.orderedProperties() {
+			prop := schema.Properties[name]
+			if value, ok := x[name]; ok {
+				x[name] = s.applyDefaults(prop, value, depth+1)
+			} else if prop.Default != nil {
+				x[name] = cloneJSON(prop.Default)
+			}
+		}
+	case []any:
+		for i, item := range x {
+			x[i] = s.applyDefaults(schema.Items, item, depth+1)
+		}
+	}
+	return v
+}
+
+// cloneJSON deep-copies maps and slices so defaults are never shared between values.
+func cloneJSON(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(x))
+		for k, item := range x {
+			out[k] = cloneJSON(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(x))
+		for i, item := range x {
+			out[i] = cloneJSON(item)
+		}
+		return out
+	}
+	return v
+}
@@ -0,0 +1,495 @@
+package jsonschema
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	durationType      = reflect.TypeFor[time.Duration]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	numberType        = reflect.TypeFor[json.Number]()
+	providerType      = reflect.TypeFor[Provider]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// Reflect returns the schema describing the JSON encoding of v, which may be a value
+// or a pointer to one. See ReflectType.
+func Reflect(v any) *Schema {
+	if v == nil {
+		return &Schema{Schema: Draft}
+	}
+	return ReflectType(reflect.TypeOf(v))
+}
+
+// For returns the schema describing the JSON encoding of values of type T.
+func For[T any]() *Schema {
+	return ReflectType(reflect.TypeFor[T]())
+}
+
+// ReflectType returns the draft 2020-12 schema describing how encoding/json encodes
+// values of type t.
+//
+// Struct fields follow encoding/json naming and precedence rules, including embedded
+// structs and the "-" and ",string" options. Fields without ",omitempty" are required,
+// which can be overridden with a `required:"true"` or `required:"false"` tag. Structs do
+// not allow additional properties. Pointer fields are nullable. time.Time is a "date-time"
+// string. Types implementing Provider supply their own schema, and recursive types are
+// emitted once under $defs and referenced with $ref.
+//
+// The following struct tags add constraints to a field:
+//
+//	description:"text"     enum:"a,b,c"       default:"value"   format:"email"
+//	minimum:"0"            maximum:"100"      minLength:"1"     maxLength:"64"
+//	pattern:"^[a-z]+$"     minItems:"1"       maxItems:"10"     title:"Name"
+func ReflectType(t reflect.Type) *Schema {
+	r := &reflector{defs: map[string]*Schema{}, names: map[reflect.Type]string{}, building: map[reflect.Type]bool{}, recursive: map[reflect.Type]bool{}}
+
+	// a pointer to the root value only matters to the decoder, not to the document
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	s := r.reflect(t)
+
+	// copy so that schemas returned by a Provider are never modified
+	root := *s
+	if len(r.defs) > 0 {
+		root.Defs = r.defs
+	}
+	root.Schema = Draft
+	return &root
+}
+
+type reflector struct {
+	defs      map[string]*Schema
+	names     map[reflect.Type]string
+	building  map[reflect.Type]bool
+	recursive map[reflect.Type]bool
+}
+
+func (r *reflector) reflect(t reflect.Type) *Schema {
+	if t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface {
+		if t.Implements(providerType) {
+			if s := reflect.Zero(t).Interface().(Provider).JSONSchema(); s != nil {
+				return s
+			}
+		} else if reflect.PointerTo(t).Implements(providerType) {
+			if s := reflect.New(t).Interface().(Provider).JSONSchema(); s != nil {
+				return s
+			}
+		}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Description: "duration in nanoseconds"}
+	case rawMessageType:
+		return &Schema{}
+	case numberType:
+		return &Schema{Type: "number"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		s := r.reflect(t.Elem())
+		if s.Ref == "" {
+			clone := *s
+			clone.Nullable = true
+			return &clone
+		}
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if t.Implements(textMarshalerType) {
+			return &Schema{Type: "string"}
+		}
+		s := &Schema{Type: "integer"}
+		if t.Kind() >= reflect.Uint && t.Kind() <= reflect.Uintptr {
+			s.Minimum = float64Ptr(0)
+		}
+		return s
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return &Schema{Type: "string", Format: "byte", Description: "base64 encoded"}
+		}
+		s := &Schema{Type: "array", Items: r.reflect(t.Elem())}
+		if t.Kind() == reflect.Array {
+			s.MinItems = intPtr(t.Len())
+			s.MaxItems = intPtr(t.Len())
+		}
+		return s
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflect(t.Elem())}
+	case reflect.Struct:
+		if t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+			return &Schema{Type: "string"}
+		}
+		return r.reflectStruct(t)
+	}
+
+	// channels, functions and complex numbers have no JSON encoding
+	return &Schema{}
+}
+
+func (r *reflector) reflectStruct(t reflect.Type) *Schema {
+	if r.building[t] {
+		r.recursive[t] = true
+		return &Schema{Ref: "#/$defs/" + r.defName(t)}
+	}
+
+	r.building[t] = true
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}, AdditionalProperties: false}
+	r.addFields(s, t)
+	delete(r.building, t)
+
+	if r.recursive[t] {
+		r.defs[r.defName(t)] = s
+		return &Schema{Ref: "#/$defs/" + r.defName(t)}
+	}
+	return s
+}
+
+// defName returns the $defs key of t: its name without package paths in type arguments,
+// qualified with its package path when another type already uses that name.
+func (r *reflector) defName(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+
+	name := sanitizeName(t.Name())
+	if name == "" {
+		name = "Type"
+	}
+	if r.nameTaken(name) && t.PkgPath() != "" {
+		name = sanitizeName(t.PkgPath()) + "." + name
+	}
+	base := name
+	for i := 2; r.nameTaken(name); i++ {
+		name = fmt.Sprintf("%s%d", base, i)
+	}
+	r.names[t] = name
+	return name
+}
+
+func (r *reflector) nameTaken(name string) bool {
+	for _, n := range r.names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeName turns a type name such as "Page[github.com/org/pkg.Item]" into a key that
+// needs no escaping in a JSON pointer, such as "Page_pkg.Item".
+func sanitizeName(name string) string {
+	var out strings.Builder
+	start := 0
+	for i := 0; i <= len(name); i++ {
+		if i < len(name) && name[i] != '[' && name[i] != ']' && name[i] != ',' {
+			continue
+		}
+		part := name[start:i]
+		if slash := strings.LastIndexByte(part, '/'); slash >= 0 && start > 0 {
+			part = part[slash+1:]
+		}
+		out.WriteString(part)
+		if i < len(name) {
+			out.WriteByte('_')
+		}
+		start = i + 1
+	}
+	return strings.Trim(strings.Map(func(c rune) rune {
+		if c == '_' || c == '.' || c == '-' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return c
+		}
+		return '_'
+	}, out.String()), "_")
+}
+
+// structField is a field of the JSON encoding of a struct, possibly promoted from an
+// embedded struct.
+type structField struct {
+	reflect.StructField
+	name   string
+	opts   string
+	tagged bool
+	index  []int
+}
+
+// structFields returns the fields encoding/json encodes for t, in its order. A field
+// promoted from an embedded struct is hidden by a shallower field with the same name,
+// and fields with the same name at the same depth hide each other unless exactly one of
+// them is named by a json tag.
+func structFields(t reflect.Type) []structField {
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+
+	var fields []structField
+	visited := map[reflect.Type]bool{}
+	for next := []embedded{{t: t}}; len(next) > 0; {
+		current := next
+		next = nil
+		for _, e := range current {
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+
+			for i := 0; i < e.t.NumField(); i++ {
+				field := e.t.Field(i)
+				ft := field.Type
+				if field.Anonymous && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if field.Anonymous {
+					if !field.IsExported() && ft.Kind() != reflect.Struct {
+						continue
+					}
+				} else if !field.IsExported() {
+					continue
+				}
+
+				tag := field.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(append([]int(nil), e.index...), i)
+
+				if field.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{t: ft, index: index})
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+				f := structField{StructField: field, name: name, opts: opts, tagged: name != "", index: index}
+				if f.name == "" {
+					f.name = field.Name
+				}
+				fields = append(fields, f)
+			}
+		}
+	}
+
+	byName := map[string][]structField{}
+	for _, f := range fields {
+		byName[f.name] = append(byName[f.name], f)
+	}
+
+	var dominant []structField
+	for _, f := range fields {
+		candidates := byName[f.name]
+		if candidates == nil {
+			continue
+		}
+		delete(byName, f.name)
+
+		depth := len(candidates[0].index)
+		var shallowest, tagged []structField
+		for _, c := range candidates {
+			if len(c.index) == depth {
+				shallowest = append(shallowest, c)
+				if c.tagged {
+					tagged = append(tagged, c)
+				}
+			}
+		}
+		switch {
+		case len(shallowest) == 1:
+			dominant = append(dominant, shallowest[0])
+		case len(tagged) == 1:
+			dominant = append(dominant, tagged[0])
+		}
+	}
+
+	slices.SortFunc(dominant, func(a, b structField) int {
+		return slices.Compare(a.index, b.index)
+	})
+	return dominant
+}
+
+func (r *reflector) addFields(s *Schema, t reflect.Type) {
+	for _, field := range structFields(t) {
+		name, opts := field.name, field.opts
+
+		var prop *Schema
+		if hasOption(opts, "string") && isScalar(field.Type) {
+			prop = &Schema{Type: "string"}
+		} else {
+			prop = r.reflect(field.Type)
+		}
+		prop = applyTags(prop, field.StructField)
+
+		s.PropertyOrder = append(s.PropertyOrder, name)
+		s.Properties[name] = prop
+
+		required := !hasOption(opts, "omitempty") && !hasOption(opts, "omitzero")
+		if v, ok := field.Tag.Lookup("required"); ok {
+			required, _ = strconv.ParseBool(v)
+		}
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyTags returns prop with the constraints declared in the field's struct tags.
+func applyTags(prop *Schema, field reflect.StructField) *Schema {
+	tags := []string{"description", "title", "enum", "default", "format", "minimum", "maximum",
+		"minLength", "maxLength", "pattern", "minItems", "maxItems"}
+
+	has := false
+	for _, tag := range tags {
+		if _, ok := field.Tag.Lookup(tag); ok {
+			has = true
+			break
+		}
+	}
+	if !has {
+		return prop
+	}
+
+	if prop.Ref != "" {
+		// keywords next to $ref would be shared by every use of the definition
+		prop = &Schema{AllOf: []*Schema{prop}}
+	} else {
+		clone := *prop
+		prop = &clone
+	}
+
+	// constraints on arrays apply to their elements, except for the array keywords
+	target := prop
+	if prop.Type == "array" && prop.Items != nil && prop.Format == "" {
+		items := *prop.Items
+		prop.Items = &items
+		target = prop.Items
+	}
+
+	if v, ok := field.Tag.Lookup("description"); ok {
+		prop.Description = v
+	}
+	if v, ok := field.Tag.Lookup("title"); ok {
+		prop.Title = v
+	}
+	if v, ok := field.Tag.Lookup("format"); ok {
+		target.Format = v
+	}
+	if v, ok := field.Tag.Lookup("pattern"); ok {
+		target.Pattern = v
+	}
+	if v, ok := field.Tag.Lookup("enum"); ok {
+		target.Enum = nil
+		for _, item := range strings.Split(v, ",") {
+			target.Enum = append(target.Enum, parseValue(target.Type, strings.TrimSpace(item)))
+		}
+	}
+	if v, ok := field.Tag.Lookup("default"); ok {
+		prop.Default = parseValue(prop.Type, v)
+	}
+	if v, ok := field.Tag.Lookup("minimum"); ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			target.Minimum = &f
+		}
+	}
+	if v, ok := field.Tag.Lookup("maximum"); ok {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			target.Maximum = &f
+		}
+	}
+	if v, ok := field.Tag.Lookup("minLength"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			target.MinLength = &n
+		}
+	}
+	if v, ok := field.Tag.Lookup("maxLength"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			target.MaxLength = &n
+		}
+	}
+	if v, ok := field.Tag.Lookup("minItems"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			prop.MinItems = &n
+		}
+	}
+	if v, ok := field.Tag.Lookup("maxItems"); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			prop.MaxItems = &n
+		}
+	}
+	return prop
+}
+
+// parseValue converts a tag value into the JSON type of the schema, falling back to the raw string.
+func parseValue(typ string, v string) any {
+	switch typ {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case "array", "object":
+		var out any
+		if err := json.Unmarshal([]byte(v), &out); err == nil {
+			return out
+		}
+	}
+	return v
+}
+
+func isScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func hasOption(opts, name string) bool {
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func intPtr(n int) *int {
+	return &n
+}
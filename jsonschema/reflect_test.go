@@ -0,0 +1,171 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type color string
+
+func (color) JSONSchema() *Schema {
+	return &Schema{Type: "string", Enum: []any{"red", "green"}}
+}
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type item struct {
+	base
+	Name     string          `json:"name" description:"item name" minLength:"1" maxLength:"32"`
+	Count    int             `json:"count,omitempty" minimum:"0" default:"1"`
+	Kind     string          `json:"kind" enum:"book, movie"`
+	Tags     []string        `json:"tags" pattern:"^[a-z]+$" maxItems:"3"`
+	Labels   map[string]int  `json:"labels,omitempty"`
+	Created  time.Time       `json:"created"`
+	Note     *string         `json:"note"`
+	Color    color           `json:"color"`
+	Ignored  string          `json:"-"`
+	Untagged bool            `required:"false"`
+	Extra    json.RawMessage `json:"extra,omitempty"`
+	Score    float64         `json:"score,string"`
+	secret   string
+	Nested   struct{ A uint8 } `json:"nested"`
+}
+
+type node struct {
+	Value    int     `json:"value"`
+	Children []*node `json:"children,omitempty"`
+}
+
+func TestReflect(t *testing.T) {
+	bt, err := json.Marshal(Reflect(&item{}))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["id", "name", "kind", "tags", "created", "note", "color", "score", "nested"],
+		"properties": {
+			"id": {"type": "string"},
+			"name": {"type": "string", "description": "item name", "minLength": 1, "maxLength": 32},
+			"count": {"type": "integer", "minimum": 0, "default": 1},
+			"kind": {"type": "string", "enum": ["book", "movie"]},
+			"tags": {"type": "array", "items": {"type": "string", "pattern": "^[a-z]+$"}, "maxItems": 3},
+			"labels": {"type": "object", "additionalProperties": {"type": "integer"}},
+			"created": {"type": "string", "format": "date-time"},
+			"note": {"type": ["string", "null"]},
+			"color": {"type": "string", "enum": ["red", "green"]},
+			"Untagged": {"type": "boolean"},
+			"extra": {},
+			"score": {"type": "string"},
+			"nested": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["A"],
+				"properties": {"A": {"type": "integer", "minimum": 0}}
+			}
+		}
+	}`, string(bt))
+
+	// properties keep declaration order
+	props := Reflect(item{})
+	props.Schema = ""
+	bt, err = json.Marshal(props)
+	require.NoError(t, err)
+	require.Regexp(t, `^\{"type":"object","properties":\{"id":[^}]*\},"name":[^}]*\},"count":`, string(bt))
+}
+
+func TestReflect_Recursive(t *testing.T) {
+	bt, err := json.Marshal(For[node]())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$ref": "#/$defs/node",
+		"$defs": {
+			"node": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["value"],
+				"properties": {
+					"value": {"type": "integer"},
+					"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}
+				}
+			}
+		}
+	}`, string(bt))
+}
+
+func TestReflect_NonStruct(t *testing.T) {
+	require.Equal(t, "array", For[[]node]().Type)
+	require.Equal(t, "string", For[string]().Type)
+	require.Equal(t, "object", For[map[string]any]().Type)
+}
+
+func TestSchema_UnmarshalJSON(t *testing.T) {
+	var s Schema
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {"b": {"type": ["integer", "null"]}, "a": {"type": "string"}},
+		"additionalProperties": {"type": "string"}
+	}`), &s))
+	require.Equal(t, []string{"b", "a"}, s.PropertyOrder)
+	require.True(t, s.Properties["b"].Nullable)
+	require.Equal(t, "integer", s.Properties["b"].Type)
+	require.Equal(t, "string", s.AdditionalProperties.(*Schema).Type)
+}
+
+type titled struct {
+	Title string `json:"title"`
+	Name  string
+	Kind  string
+}
+
+type labelled struct {
+	Name int
+	K    int `json:"Kind"`
+}
+
+type shadowing struct {
+	titled
+	labelled
+}
+
+type outer struct {
+	titled
+	Name bool
+}
+
+func TestReflect_EmbeddedPrecedence(t *testing.T) {
+	// at the same depth untagged fields cancel out and a tagged field beats an untagged one
+	s := For[shadowing]()
+	require.Equal(t, []string{"title", "Kind"}, s.PropertyOrder)
+	require.Equal(t, "integer", s.Properties["Kind"].Type)
+
+	// the shallower field wins over a promoted one
+	s = For[outer]()
+	require.Equal(t, []string{"title", "Kind", "Name"}, s.PropertyOrder)
+	require.Equal(t, "boolean", s.Properties["Name"].Type)
+}
+
+type page[T any] struct {
+	Items []T      `json:"items"`
+	Next  *page[T] `json:"next"`
+}
+
+type Duration struct{}
+
+func TestReflect_DefNames(t *testing.T) {
+	s := For[page[node]]()
+	require.Equal(t, "#/$defs/page_jsonschema.node", s.Ref)
+	require.Contains(t, s.Defs, "page_jsonschema.node")
+
+	r := &reflector{names: map[reflect.Type]string{}}
+	require.Equal(t, "Duration", r.defName(reflect.TypeFor[Duration]()))
+	require.Equal(t, "time.Duration", r.defName(reflect.TypeFor[time.Duration]()))
+	require.Equal(t, "Duration", r.defName(reflect.TypeFor[Duration]()))
+}
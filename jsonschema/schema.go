@@ -0,0 +1,243 @@
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Draft is the JSON Schema dialect produced by this package.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+// Only the keywords needed to describe Go values and LLM tool parameters are modelled.
+type Schema struct {
+	Schema      string `json:"$schema,omitempty"`
+	Ref         string `json:"$ref,omitempty"`
+	Type        string `json:"-"`
+	Nullable    bool   `json:"-"` // the value may also be null; encoded as a type array
+	Format      string `json:"format,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Enum        []any  `json:"enum,omitempty"`
+	Const       any    `json:"const,omitempty"`
+	Default     any    `json:"default,omitempty"`
+
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+
+	Items    *Schema `json:"items,omitempty"`
+	MinItems *int    `json:"minItems,omitempty"`
+	MaxItems *int    `json:"maxItems,omitempty"`
+
+	// Properties are encoded in PropertyOrder first, followed by any remaining
+	// properties in lexical order.
+	Properties    map[string]*Schema `json:"-"`
+	PropertyOrder []string           `json:"-"`
+	Required      []string           `json:"required,omitempty"`
+	// AdditionalProperties is nil (unspecified), a bool or a *Schema.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	AllOf []*Schema `json:"allOf,omitempty"`
+
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Provider lets a type supply its own schema instead of the reflected one.
+type Provider interface {
+	JSONSchema() *Schema
+}
+
+// MarshalJSON encodes the schema, keeping properties in declaration order.
+func (s Schema) MarshalJSON() ([]byte, error) {
+	type plain Schema
+	bt, err := json.Marshal(plain(s))
+	if err != nil {
+		return nil, err
+	}
+
+	var extra bytes.Buffer
+	if s.Type != "" {
+		extra.WriteString(`"type":`)
+		if s.Nullable && s.Type != "null" {
+			extra.WriteString(fmt.Sprintf("[%q,\"null\"]", s.Type))
+		} else {
+			extra.WriteString(fmt.Sprintf("%q", s.Type))
+		}
+	}
+
+	if len(s.Properties) > 0 {
+		if extra.Len() > 0 {
+			extra.WriteByte(',')
+		}
+		extra.WriteString(`"properties":{`)
+		for i, name := range s.orderedProperties() {
+			if i > 0 {
+				extra.WriteByte(',')
+			}
+			key, _ := json.Marshal(name)
+			value, err := json.Marshal(s.Properties[name])
+			if err != nil {
+				return nil, err
+			}
+			extra.Write(key)
+			extra.WriteByte(':')
+			extra.Write(value)
+		}
+		extra.WriteByte('}')
+	}
+
+	if extra.Len() == 0 {
+		return bt, nil
+	}
+
+	// splice the extra keywords in front of the plain ones
+	out := make([]byte, 0, len(bt)+extra.Len()+1)
+	out = append(out, '{')
+	out = append(out, extra.Bytes()...)
+	if len(bt) > 2 {
+		out = append(out, ',')
+	}
+	out = append(out, bt[1:]...)
+	return out, nil
+}
+
+// UnmarshalJSON decodes a schema, recording the order in which properties appear.
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	type plain Schema
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Type                 json.RawMessage `json:"type"`
+		Properties           json.RawMessage `json:"properties"`
+		AdditionalProperties json.RawMessage `json:"additionalProperties"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*s = Schema(p)
+
+	if len(raw.Type) > 0 {
+		var types []string
+		if err := json.Unmarshal(raw.Type, &s.Type); err != nil {
+			if err := json.Unmarshal(raw.Type, &types); err != nil {
+				return fmt.Errorf("invalid type: %s", raw.Type)
+			}
+			for _, t := range types {
+				if t == "null" {
+					s.Nullable = true
+				} else if s.Type == "" {
+					s.Type = t
+				}
+			}
+			if s.Type == "" && s.Nullable {
+				s.Type, s.Nullable = "null", false
+			}
+		}
+	}
+
+	if len(raw.AdditionalProperties) > 0 {
+		var b bool
+		if err := json.Unmarshal(raw.AdditionalProperties, &b); err == nil {
+			s.AdditionalProperties = b
+		} else {
+			var sub Schema
+			if err := json.Unmarshal(raw.AdditionalProperties, &sub); err != nil {
+				return err
+			}
+			s.AdditionalProperties = &sub
+		}
+	}
+
+	if len(raw.Properties) > 0 && string(raw.Properties) != "null" {
+		return s.unmarshalProperties(raw.Properties)
+	}
+	return nil
+}
+
+func (s *Schema) unmarshalProperties(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return fmt.Errorf("properties must be an object")
+	}
+
+	s.Properties = map[string]*Schema{}
+	s.PropertyOrder = nil
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+
+		var sub Schema
+		if err := dec.Decode(&sub); err != nil {
+			return fmt.Errorf("property %q: %w", name, err)
+		}
+		s.Properties[name] = &sub
+		s.PropertyOrder = append(s.PropertyOrder, name)
+	}
+	return nil
+}
+
+// FromMap converts a schema written as nested maps, such as tool.Tool.ParameterSchema,
+// into a Schema.
+func FromMap(m map[string]interface{}) (*Schema, error) {
+	bt, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	var s Schema
+	if err := json.Unmarshal(bt, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// ToMap converts the schema into nested maps, the representation used by tool.Tool.ParameterSchema.
+// Property order is not preserved by maps.
+func (s *Schema) ToMap() map[string]interface{} {
+	bt, err := json.Marshal(s)
+	if err != nil {
+		return map[string]interface{}{}
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(bt, &m); err != nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func (s *Schema) orderedProperties() []string {
+	names := make([]string, 0, len(s.Properties))
+	seen := map[string]bool{}
+	for _, name := range s.PropertyOrder {
+		if _, ok := s.Properties[name]; ok && !seen[name] {
+			names = append(names, name)
+			seen[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range s.Properties {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
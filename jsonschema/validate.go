@@ -0,0 +1,345 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ValidationError lists every violation found while validating a value.
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Violation is a single schema violation at a location in the instance.
+type Violation struct {
+	// Path is a JSON Pointer to the offending value, "" for the root.
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	path := v.Path
+	if path == "" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s: %s", path, v.Message)
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.String()
+	}
+	return "schema validation failed: " + strings.Join(parts, "; ")
+}
+
+// Validate checks a decoded JSON value, as produced by json.Unmarshal into an any,
+// against the schema. It returns a *ValidationError listing all violations, or nil.
+func (s *Schema) Validate(v any) error {
+	val := &validator{root: s}
+	val.validate(s, v, "")
+	if len(val.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: val.violations}
+}
+
+// ValidateJSON decodes data and validates it against the schema.
+func (s *Schema) ValidateJSON(data []byte) error {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	return s.Validate(v)
+}
+
+type validator struct {
+	root       *Schema
+	violations []Violation
+	depth      int
+}
+
+func (val *validator) fail(path, format string, args ...any) {
+	val.violations = append(val.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (val *validator) validate(s *Schema, v any, path string) {
+	if s == nil {
+		return
+	}
+
+	val.depth++
+	defer func() { val.depth-- }()
+	if val.depth > 64 {
+		val.fail(path, "schema nesting too deep")
+		return
+	}
+
+	if s.Ref != "" {
+		ref, ok := val.resolve(s.Ref)
+		if !ok {
+			val.fail(path, "unresolvable reference %q", s.Ref)
+			return
+		}
+		val.validate(ref, v, path)
+	}
+
+	for _, sub := range s.AllOf {
+		val.validate(sub, v, path)
+	}
+	if len(s.AnyOf) > 0 && val.countMatches(s.AnyOf, v, path) == 0 {
+		val.fail(path, "does not match any of the allowed schemas")
+	}
+	if len(s.OneOf) > 0 {
+		if n := val.countMatches(s.OneOf, v, path); n != 1 {
+			val.fail(path, "must match exactly one schema, matched %d", n)
+		}
+	}
+
+	// null is allowed by a nullable schema whatever its enum or const
+	if v == nil && s.Nullable {
+		return
+	}
+
+	if s.Const != nil && !equalJSON(v, s.Const) {
+		val.fail(path, "must be %s", encode(s.Const))
+	}
+	if s.Enum != nil {
+		found := false
+		for _, e := range s.Enum {
+			if equalJSON(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			val.fail(path, "must be one of %s", encode(s.Enum))
+		}
+	}
+
+	if v == nil {
+		if s.Type != "" && s.Type != "null" && !s.Nullable {
+			val.fail(path, "expected %s, got null", s.Type)
+		}
+		return
+	}
+
+	if s.Type != "" && !hasType(v, s.Type) {
+		val.fail(path, "expected %s, got %s", s.Type, typeName(v))
+		return
+	}
+
+	switch x := v.(type) {
+	case string:
+		val.validateString(s, x, path)
+	case float64:
+		val.validateNumber(s, x, path)
+	case json.Number:
+		if f, err := x.Float64(); err == nil {
+			val.validateNumber(s, f, path)
+		}
+	case []any:
+		val.validateArray(s, x, path)
+	case map[string]any:
+		val.validateObject(s, x, path)
+	}
+}
+
+func (val *validator) countMatches(schemas []*Schema, v any, path string) int {
+	n := 0
+	for _, sub := range schemas {
+		probe := &validator{root: val.root, depth: val.depth}
+		probe.validate(sub, v, path)
+		if len(probe.violations) == 0 {
+			n++
+		}
+	}
+	return n
+}
+
+func (val *validator) resolve(ref string) (*Schema, bool) {
+	if ref == "#" {
+		return val.root, true
+	}
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, false
+	}
+	s, ok := val.root.Defs[name]
+	return s, ok
+}
+
+func (val *validator) validateString(s *Schema, x string, path string) {
+	n := utf8.RuneCountInString(x)
+	if s.MinLength != nil && n < *s.MinLength {
+		val.fail(path, "must be at least %d characters long", *s.MinLength)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		val.fail(path, "must be at most %d characters long", *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			val.fail(path, "invalid pattern %q in schema", s.Pattern)
+		} else if !re.MatchString(x) {
+			val.fail(path, "must match pattern %q", s.Pattern)
+		}
+	}
+
+	switch s.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, x); err != nil {
+			val.fail(path, "must be an RFC 3339 date-time")
+		}
+	case "date":
+		if _, err := time.Parse(time.DateOnly, x); err != nil {
+			val.fail(path, "must be a date in YYYY-MM-DD format")
+		}
+	}
+}
+
+func (val *validator) validateNumber(s *Schema, x float64, path string) {
+	if s.Minimum != nil && x < *s.Minimum {
+		val.fail(path, "must be >= %v", *s.Minimum)
+	}
+	if s.Maximum != nil && x > *s.Maximum {
+		val.fail(path, "must be <= %v", *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && x <= *s.ExclusiveMinimum {
+		val.fail(path, "must be > %v", *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && x >= *s.ExclusiveMaximum {
+		val.fail(path, "must be < %v", *s.ExclusiveMaximum)
+	}
+}
+
+func (val *validator) validateArray(s *Schema, x []any, path string) {
+	if s.MinItems != nil && len(x) < *s.MinItems {
+		val.fail(path, "must contain at least %d items", *s.MinItems)
+	}
+	if s.MaxItems != nil && len(x) > *s.MaxItems {
+		val.fail(path, "must contain at most %d items", *s.MaxItems)
+	}
+	if s.Items != nil {
+		for i, item := range x {
+			val.validate(s.Items, item, fmt.Sprintf("%s/%d", path, i))
+		}
+	}
+}
+
+func (val *validator) validateObject(s *Schema, x map[string]any, path string) {
+	for _, name := range s.Required {
+		if _, ok := x[name]; !ok {
+			val.fail(path, "missing required property %q", name)
+		}
+	}
+
+	keys := make([]string, 0, len(x))
+	for k := range x {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		child := path + "/" + escapePointer(k)
+		if prop, ok := s.Properties[k]; ok {
+			val.validate(prop, x[k], child)
+			continue
+		}
+
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				val.fail(child, "unknown property %q", k)
+			}
+		case *Schema:
+			val.validate(additional, x[k], child)
+		}
+	}
+}
+
+func hasType(v any, typ string) bool {
+	switch typ {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "number":
+		switch v.(type) {
+		case float64, json.Number:
+			return true
+		}
+		return false
+	case "integer":
+		switch x := v.(type) {
+		case float64:
+			return x == math.Trunc(x) && !math.IsInf(x, 0)
+		case json.Number:
+			_, err := x.Int64()
+			return err == nil
+		}
+		return false
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+func typeName(v any) string {
+	switch x := v.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if x == math.Trunc(x) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// equalJSON compares values by their JSON encoding so that numeric types from
+// struct tags (int64) and from decoding (float64) compare equal.
+func equalJSON(a, b any) bool {
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	return encode(a) == encode(b)
+}
+
+func encode(v any) string {
+	bt, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bt)
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,60 @@
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchema_Validate(t *testing.T) {
+	schema := For[item]()
+
+	require.NoError(t, schema.ValidateJSON([]byte(`{
+		"id": "1", "name": "Dune", "kind": "book", "tags": ["scifi"], "created": "2024-01-02T03:04:05Z",
+		"note": null, "color": "red", "score": "4.5", "nested": {"A": 1}, "count": 2
+	}`)))
+
+	err := schema.ValidateJSON([]byte(`{
+		"id": 1, "name": "", "kind": "game", "tags": ["Sci-Fi", "a", "b", "c"], "created": "yesterday",
+		"color": "blue", "score": "4.5", "nested": {"A": -1, "B": 2}, "count": 1.5
+	}`))
+
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.ElementsMatch(t, []string{
+		"/: missing required property \"note\"",
+		"/id: expected string, got integer",
+		"/name: must be at least 1 characters long",
+		"/kind: must be one of [\"book\",\"movie\"]",
+		"/tags: must contain at most 3 items",
+		"/tags/0: must match pattern \"^[a-z]+$\"",
+		"/created: must be an RFC 3339 date-time",
+		"/color: must be one of [\"red\",\"green\"]",
+		"/nested/A: must be >= 0",
+		"/nested/B: unknown property \"B\"",
+		"/count: expected integer, got number",
+	}, violations(verr))
+
+	recursive := For[node]()
+	require.NoError(t, recursive.ValidateJSON([]byte(`{"value": 1, "children": [{"value": 2, "children": [{"value": 3}]}]}`)))
+	require.Error(t, recursive.ValidateJSON([]byte(`{"value": 1, "children": [{"children": []}]}`)))
+}
+
+func TestSchema_ValidateNullableEnum(t *testing.T) {
+	schema := For[struct {
+		Size *string `json:"size" enum:"small,large"`
+	}]()
+
+	require.NoError(t, schema.ValidateJSON([]byte(`{"size": null}`)))
+	require.NoError(t, schema.ValidateJSON([]byte(`{"size": "small"}`)))
+	require.EqualError(t, schema.ValidateJSON([]byte(`{"size": "huge"}`)), `schema validation failed: /size: must be one of ["small","large"]`)
+}
+
+func violations(err *ValidationError) []string {
+	out := make([]string, len(err.Violations))
+	for i, v := range err.Violations {
+		out[i] = v.String()
+	}
+	return out
+}
@@ -2,8 +2,13 @@ package gothought
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
+	"slices"
 
+	"github.com/gobenpark/gothought/jsonschema"
 	"github.com/gobenpark/gothought/tool"
 )
 
@@ -12,18 +17,55 @@ const (
 	FinishReasonToolCalls = "tool_calls"
 )
 
+// ErrEmptyConversation is returned when a query is made before any message was added.
+var ErrEmptyConversation = errors.New("conversation has no messages")
+
+// ErrToolConflict is returned by queries when AddTool was called with a different tool
+// under a name that is already registered.
+var ErrToolConflict = errors.New("tool name conflict")
+
+// ErrUnknownTool is returned when WithTools names a tool or toolkit that is not registered.
+var ErrUnknownTool = errors.New("unknown tool")
+
 type LanguageModel struct {
 	tools         map[string]tool.Tool
 	provider      Provider
 	messages      []Message
 	maxIterations int // maxIterations default int values 10
+	cache         Cache
+	// repairAttempts is how often QWith asks the model to fix invalid output, default 2
+	repairAttempts    int
+	toolResultHandler ToolResultHandler
+	// toolkits maps toolkit names to the names of their registered tools
+	toolkits     map[string][]string
+	toolSelector ToolSelector
+	// toolPolicies are the execution policies by tool name, defaultToolPolicy applies to all others
+	toolPolicies      map[string]ToolPolicy
+	defaultToolPolicy ToolPolicy
+	// toolConcurrency is how many tool calls of one response run at the same time, default 1
+	toolConcurrency int
+	limiter         *toolLimiter
+	// toolCaching is nil unless tool results are cached
+	toolCaching *toolCaching
+	// conflicts are the names AddTool was called with for a different tool, in order,
+	// reported by queries until they are removed
+	conflicts []string
 }
 
+// ToolResultHandler is called with the result of every tool call made while answering a query,
+// giving access to the parts of a tool.Result that are not sent to the model.
+type ToolResultHandler func(ctx context.Context, call ToolCalls, result *tool.Result)
+
 func NewLanguageModel(p Provider, options ...Option) *LanguageModel {
 	cli := &LanguageModel{
-		provider:      p,
-		maxIterations: 10,
-		tools:         map[string]tool.Tool{},
+		provider:        p,
+		maxIterations:   10,
+		tools:           map[string]tool.Tool{},
+		toolkits:        map[string][]string{},
+		repairAttempts:  2,
+		toolPolicies:    map[string]ToolPolicy{},
+		toolConcurrency: 1,
+		limiter:         &toolLimiter{},
 	}
 
 	for _, option := range options {
@@ -33,6 +75,14 @@ func NewLanguageModel(p Provider, options ...Option) *LanguageModel {
 	return cli
 }
 
+// clone returns a LanguageModel sharing the provider, tools and options of l
+// with an independent copy of its conversation history.
+func (l *LanguageModel) clone() *LanguageModel {
+	c := *l
+	c.messages = append([]Message(nil), l.messages...)
+	return &c
+}
+
 // SetPrompts replaces the entire conversation history with a new set of messages.
 // This allows for completely resetting or initializing the conversation context
 // with predefined messages of various roles (system, user, AI, etc.).
@@ -43,11 +93,73 @@ func (l *LanguageModel) SetPrompts(prompts []Message) {
 // AddTool registers a new tool with the language model.
 // Tools allow the language model to perform actions or access external functionality
 // during the conversation through function calling.
+// Registering a different tool under a name that is already taken is an error wrapping
+// ErrToolConflict, reported by queries until the name is removed with RemoveTool or
+// RemoveToolkit; remove the existing tool first to replace it.
 func (l *LanguageModel) AddTool(t tool.Tool) *LanguageModel {
+	if existing, ok := l.tools[t.Name()]; ok && !sameTool(existing, t) {
+		if !slices.Contains(l.conflicts, t.Name()) {
+			l.conflicts = append(l.conflicts, t.Name())
+		}
+		return l
+	}
 	l.tools[t.Name()] = t
 	return l
 }
 
+// AddToolkit registers all tools of a toolkit under their prefixed names.
+// The toolkit name can be passed to WithTools and RemoveToolkit.
+func (l *LanguageModel) AddToolkit(k *tool.Toolkit) *LanguageModel {
+	for _, t := range k.Tools() {
+		l.AddTool(t)
+		if sameTool(l.tools[t.Name()], t) && !slices.Contains(l.toolkits[k.Name()], t.Name()) {
+			l.toolkits[k.Name()] = append(l.toolkits[k.Name()], t.Name())
+		}
+	}
+	return l
+}
+
+// RemoveTool unregisters the named tools.
+func (l *LanguageModel) RemoveTool(names ...string) *LanguageModel {
+	for _, name := range names {
+		delete(l.tools, name)
+		l.conflicts = slices.DeleteFunc(l.conflicts, func(conflict string) bool { return conflict == name })
+		for kit, members := range l.toolkits {
+			l.toolkits[kit] = slices.DeleteFunc(members, func(member string) bool { return member == name })
+		}
+	}
+	return l
+}
+
+// RemoveToolkit unregisters all tools added with the toolkit name.
+func (l *LanguageModel) RemoveToolkit(name string) *LanguageModel {
+	members := l.toolkits[name]
+	delete(l.toolkits, name)
+	return l.RemoveTool(members...)
+}
+
+// sameTool reports whether a and b are the same tool, so registering a tool twice is allowed.
+// Wrappers such as the prefixed tools of a toolkit are compared by name and wrapped tool,
+// since Toolkit.Tools returns new wrappers on every call.
+func sameTool(a, b tool.Tool) bool {
+	if a.Name() != b.Name() {
+		return false
+	}
+	a, b = unwrapTool(a), unwrapTool(b)
+	return reflect.TypeOf(a) == reflect.TypeOf(b) && reflect.TypeOf(a).Comparable() && a == b
+}
+
+// unwrapTool returns the innermost tool wrapped by t.
+func unwrapTool(t tool.Tool) tool.Tool {
+	for {
+		wrapper, ok := t.(interface{ Unwrap() tool.Tool })
+		if !ok {
+			return t
+		}
+		t = wrapper.Unwrap()
+	}
+}
+
 // SystemPrompt adds a system instruction message to the conversation.
 // It appends a new message with the "system" role to the client's message list.
 // System messages are typically used to set the behavior of the language model.
@@ -89,67 +201,187 @@ func (l *LanguageModel) HumanPrompt(prompt string) *LanguageModel {
 // Q executes a query to the language model and returns the response.
 // It manages tool calls through multiple iterations if necessary,
 // up to the configured maximum number of iterations.
-func (l *LanguageModel) Q(ctx context.Context) (*Message, error) {
+func (l *LanguageModel) Q(ctx context.Context, options ...CallOption) (*Message, error) {
+	cfg := newCallConfig(options)
+	tools, err := l.toolsFor(ctx, l.messages, cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	messages := l.messages
+	if l.cache != nil {
+		cached, ok, err := l.cache.Get(ctx, tools, l.messages)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return cached, nil
+		}
+	}
 
+	response, _, err := l.run(ctx, cfg, tools, append([]Message(nil), l.messages...), func(ctx context.Context, p Provider, tools map[string]tool.Tool, messages []Message) (*Message, string, error) {
+		return p.Generate(ctx, tools, messages)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if l.cache != nil {
+		if err := l.cache.Set(ctx, tools, l.messages, response); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// generateFunc makes a single request to p offering tools.
+type generateFunc func(ctx context.Context, p Provider, tools map[string]tool.Tool, messages []Message) (*Message, string, error)
+
+// run is the agent loop shared by Q and QWith. It calls generate with the conversation,
+// executes the requested tools and feeds their results back until the model stops,
+// returning the final response and the conversation including all tool exchanges.
+// The tool choice of every iteration is taken from cfg.
+func (l *LanguageModel) run(ctx context.Context, cfg *callConfig, tools map[string]tool.Tool, messages []Message, generate generateFunc) (*Message, []Message, error) {
+	called := false
+	cache := l.toolCaching.runCache()
 	for i := 0; i < l.maxIterations; i++ {
-		response, finishReason, err := l.provider.Generate(ctx, l.tools, messages)
+		p, offered, err := l.stepProvider(cfg, tools, i, called)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+
+		response, finishReason, err := generate(ctx, p, offered, messages)
+		if err != nil {
+			return nil, nil, err
 		}
 
 		switch finishReason {
 		case FinishReasonStop:
-			return response, nil
+			return response, messages, nil
 		case FinishReasonToolCalls:
+			called = true
 			messages = append(messages, *response)
 
-			for _, tl := range response.ToolCalls {
-				tres, err := l.tools[tl.Function.Name].Call(ctx, tl.Function.Arguments)
-				if err != nil {
-					return nil, err
+			results, err := l.callTools(ctx, tools, response.ToolCalls, cache)
+			if err != nil {
+				return nil, nil, err
+			}
+			for i, tl := range response.ToolCalls {
+				if l.toolResultHandler != nil {
+					l.toolResultHandler(ctx, tl, results[i])
 				}
 				messages = append(messages, Message{
 					Role:       "tool",
 					ToolCallID: tl.ID,
-					Message:    tres,
+					Message:    results[i].Content(),
+					ToolResult: results[i],
 				})
 			}
 		}
 	}
-	return nil, errors.New("max iterations reached")
+	return nil, nil, errors.New("max iterations reached")
 }
 
 // QStream executes a streaming query to the language model.
 // It checks if the provider supports streaming capabilities and
 // processes the response through the provided callback function.
+// Streaming does not run the tool loop, so no tools are offered to the model.
 func (l *LanguageModel) QStream(ctx context.Context, callback func(Message) error) error {
-	if p, ok := any(l.provider).(StreamingCapable); ok {
+	if p, ok := l.provider.(StreamingCapable); ok {
 		return p.GenerateStreaming(ctx, l.messages, callback)
 	}
 
 	return errors.New("streaming not supported for this provider")
 }
 
-// It takes a context and an interface object that defines the structure
-// of the expected output. The function appends a schema prompt to the last message,
-// processes the response from the provider, and parses the result into the provided object.
-// This is particularly useful for getting structured, type-safe responses from the language model.
-func (o *LanguageModel) QWith(ctx context.Context, oj interface{}) error {
-	msgLen := len(o.messages)
-	msg := o.messages[msgLen-1]
+// QWith queries the model and decodes its answer into oj, a pointer to the expected output.
+// It takes a context and an interface object that defines the structure of the expected
+// output. The tool loop runs exactly as in Q, and the final response is parsed into the
+// provided object. This is particularly useful for getting structured, type-safe responses
+// from the language model. The conversation history is not modified.
+//
+// When the provider implements StructuredOutputCapable and reports support, the schema is
+// enforced natively by the provider and the response is decoded directly; otherwise the
+// schema is sent as a transient system instruction after the conversation and the JSON is
+// extracted leniently with ExtractJSON. The decoded JSON is validated against the schema
+// and, if oj implements Validator, by oj itself. Invalid output is sent back to the model
+// together with the validation errors for up to the configured number of repair attempts
+// (see WithRepairAttempts).
+func (o *LanguageModel) QWith(ctx context.Context, oj interface{}, options ...CallOption) error {
+	if len(o.messages) == 0 {
+		return ErrEmptyConversation
+	}
+
+	cfg := newCallConfig(options)
+	tools, err := o.toolsFor(ctx, o.messages, cfg)
+	if err != nil {
+		return err
+	}
+
+	schema := jsonschema.Reflect(oj)
+	format := ResponseFormat{Name: schemaName(oj), Schema: schema}
+	instruction := Message{Role: "system", Message: GenerateSchemaPrompt(oj)}
 
-	msg.Message += "\n\n" + GenerateSchemaPrompt(oj)
-	o.messages[msgLen-1] = msg
+	generate := func(ctx context.Context, p Provider, tools map[string]tool.Tool, messages []Message) (*Message, string, error) {
+		if sp, ok := p.(StructuredOutputCapable); ok && sp.SupportsStructuredOutput() {
+			return sp.GenerateStructured(ctx, tools, messages, format)
+		}
+		request := append(messages[:len(messages):len(messages)], instruction)
+		return p.Generate(ctx, tools, request)
+	}
+
+	messages := append([]Message(nil), o.messages...)
+
+	var lastErr error
+	for attempt := 0; attempt <= o.repairAttempts; attempt++ {
+		res, conversation, err := o.run(ctx, cfg, tools, messages, generate)
+		if err != nil {
+			return err
+		}
+
+		lastErr = decodeStructured(res.Message, schema, oj)
+		if lastErr == nil {
+			return nil
+		}
+
+		messages = append(conversation,
+			Message{Role: "assistant", Message: res.Message},
+			Message{Role: "user", Message: repairPrompt(lastErr)},
+		)
+	}
+	return fmt.Errorf("invalid structured output: %w", lastErr)
+}
 
-	res, _, err := o.provider.Generate(ctx, o.tools, o.messages)
+// decodeStructured extracts the JSON in text, validates it against schema and decodes it into oj.
+// When text contains several JSON values, the longest one conforming to schema is used.
+func decodeStructured(text string, schema *jsonschema.Schema, oj interface{}) error {
+	candidates, err := jsonCandidates(text)
 	if err != nil {
 		return err
 	}
 
-	if err := ParsePrompt(oj, res.Message); err != nil {
+	raw := ""
+	for _, candidate := range candidates {
+		if err := schema.ValidateJSON([]byte(candidate)); err == nil {
+			raw = candidate
+			break
+		}
+	}
+	if raw == "" {
+		// report the violations of the most likely document
+		return schema.ValidateJSON([]byte(candidates[0]))
+	}
+
+	// start from the zero value so fields of a rejected attempt do not leak into the result
+	if rv := reflect.ValueOf(oj); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv.Elem().Set(reflect.Zero(rv.Elem().Type()))
+	}
+	if err := json.Unmarshal([]byte(raw), oj); err != nil {
 		return err
 	}
-	return nil
+	return validate(oj)
+}
+
+func repairPrompt(err error) string {
+	return "Your previous response was not valid: " + err.Error() +
+		"\n\nRespond again with the complete corrected JSON only, conforming exactly to the requested schema."
 }
@@ -0,0 +1,124 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	calls     int
+	responses []Message
+	messages  []Message
+	tools     map[string]tool.Tool
+}
+
+func (f *fakeProvider) Generate(ctx context.Context, tools map[string]tool.Tool, messages []Message) (*Message, string, error) {
+	f.messages = messages
+	f.tools = tools
+	res := f.responses[f.calls%len(f.responses)]
+	f.calls++
+	if len(res.ToolCalls) > 0 {
+		return &res, FinishReasonToolCalls, nil
+	}
+	return &res, FinishReasonStop, nil
+}
+
+type fakeTool struct {
+	name   string
+	result string
+	schema map[string]interface{}
+	calls  int
+	params []string
+}
+
+func (f *fakeTool) Name() string        { return f.name }
+func (f *fakeTool) Description() string { return "fake tool" }
+func (f *fakeTool) ParameterSchema() map[string]interface{} {
+	if f.schema != nil {
+		return f.schema
+	}
+	return map[string]interface{}{"type": "object"}
+}
+func (f *fakeTool) Call(ctx context.Context, params string) (string, error) {
+	f.calls++
+	f.params = append(f.params, params)
+	return f.result, nil
+}
+
+func TestLanguageModel_Q(t *testing.T) {
+	call := ToolCalls{ID: "call_1", Type: "function"}
+	call.Function.Name = "lookup"
+	call.Function.Arguments = `{"q": "go"}`
+
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{call}},
+		{Message: "Go is a programming language."},
+	}}
+	lookup := &fakeTool{name: "lookup", result: "Go was designed at Google."}
+	model := NewLanguageModel(provider).AddTool(lookup).HumanPrompt("What is Go?")
+
+	res, err := model.Q(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, "Go is a programming language.", res.Message)
+	require.Len(t, lookup.params, 1)
+	require.JSONEq(t, `{"q": "go"}`, lookup.params[0])
+	require.Equal(t, "call_1", provider.messages[2].ToolCallID)
+	require.Len(t, model.messages, 1)
+}
+
+func TestLanguageModel_Toolkits(t *testing.T) {
+	ctx := context.TODO()
+	provider := &fakeProvider{responses: []Message{{Message: "ok"}}}
+
+	github := tool.NewToolkit("github", &fakeTool{name: "search"}, &fakeTool{name: "create_issue"})
+	jira := tool.NewToolkit("jira", &fakeTool{name: "search"})
+	clock := &fakeTool{name: "now"}
+
+	model := NewLanguageModel(provider).AddToolkit(github).AddToolkit(jira).AddTool(clock).AddTool(clock).HumanPrompt("hi")
+	_, err := model.Q(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"github_search", "github_create_issue", "jira_search", "now"}, lo.Keys(provider.tools))
+
+	_, err = model.Q(ctx, WithTools("jira", "now"))
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"jira_search", "now"}, lo.Keys(provider.tools))
+
+	_, err = model.Q(ctx, WithTools())
+	require.NoError(t, err)
+	require.Empty(t, provider.tools)
+
+	_, err = model.Q(ctx, WithTools("slack"))
+	require.ErrorIs(t, err, ErrUnknownTool)
+
+	model.RemoveToolkit("github").RemoveTool("now")
+	_, err = model.Q(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"jira_search"}, lo.Keys(provider.tools))
+
+	// a different tool with a taken name is reported by the next query
+	model.AddTool(&fakeTool{name: "jira_search"})
+	_, err = model.Q(ctx)
+	require.ErrorIs(t, err, ErrToolConflict)
+
+	// removing the name clears the conflict, so the tool can be replaced
+	replacement := &fakeTool{name: "jira_search"}
+	model.RemoveTool("jira_search").AddTool(replacement)
+	_, err = model.Q(ctx)
+	require.NoError(t, err)
+	require.Same(t, replacement, provider.tools["jira_search"])
+
+	// adding a toolkit again is not a conflict and does not duplicate its members
+	model.RemoveTool("jira_search").AddToolkit(jira).AddToolkit(jira)
+	_, err = model.Q(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"jira_search"}, model.toolkits["jira"])
+
+	model.RemoveToolkit("jira")
+	_, err = model.Q(ctx)
+	require.NoError(t, err)
+	require.Empty(t, provider.tools)
+}
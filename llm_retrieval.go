@@ -0,0 +1,162 @@
+package gothought
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gobenpark/gothought/retriever"
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// LLMReranker implements retriever.Reranker by asking a language model to grade how
+// relevant each candidate is to the query. Candidates are graded in batches with
+// structured output, and every batch runs on a copy of the model's conversation so
+// that a system prompt set on the model can steer the grading.
+type LLMReranker struct {
+	model     *LanguageModel
+	batchSize int
+	minScore  float32
+}
+
+var _ retriever.Reranker = (*LLMReranker)(nil)
+
+type rerankGrades struct {
+	Grades []rerankGrade `json:"grades" description:"one grade for every passage, in any order"`
+}
+
+type rerankGrade struct {
+	ID    int     `json:"id" description:"the number of the passage"`
+	Score float32 `json:"score" description:"relevance from 0 (unrelated) to 10 (fully answers the query)"`
+}
+
+// NewLLMReranker creates a reranker grading batchSize candidates per model call.
+// Candidates graded below minScore, on a 0 to 1 scale, are dropped.
+func NewLLMReranker(model *LanguageModel, batchSize int, minScore float32) *LLMReranker {
+	if batchSize <= 0 {
+		batchSize = 10
+	}
+	return &LLMReranker{model: model, batchSize: batchSize, minScore: minScore}
+}
+
+// Rerank grades every result and returns them by descending grade. The Score of each
+// returned result is replaced by its grade scaled to [0, 1]; ties keep their original order.
+func (r *LLMReranker) Rerank(ctx context.Context, query string, results []vectorstore.Result) ([]vectorstore.Result, error) {
+	scored := make([]vectorstore.Result, len(results))
+	copy(scored, results)
+
+	for start := 0; start < len(scored); start += r.batchSize {
+		batch := scored[start:min(start+r.batchSize, len(scored))]
+
+		var sb strings.Builder
+		sb.WriteString("Grade how relevant each passage is to the query.\n\n")
+		sb.WriteString(fmt.Sprintf("Query: %s\n\n", query))
+		for i, result := range batch {
+			sb.WriteString(fmt.Sprintf("Passage %d:\n%s\n\n", i+1, strings.TrimSpace(result.Content)))
+		}
+
+		var grades rerankGrades
+		if err := r.model.clone().HumanPrompt(sb.String()).QWith(ctx, &grades); err != nil {
+			return nil, err
+		}
+
+		for i := range batch {
+			batch[i].Score = 0
+		}
+		for _, grade := range grades.Grades {
+			if grade.ID < 1 || grade.ID > len(batch) {
+				continue
+			}
+			batch[grade.ID-1].Score = min(max(grade.Score, 0), 10) / 10
+		}
+	}
+
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+
+	out := scored[:0]
+	for _, result := range scored {
+		if result.Score >= r.minScore {
+			out = append(out, result)
+		}
+	}
+	return out, nil
+}
+
+// QueryExpansion selects how LLMQueryExpander rewrites queries.
+type QueryExpansion int
+
+const (
+	// MultiQueryExpansion asks the model for alternative phrasings of the query.
+	MultiQueryExpansion QueryExpansion = iota
+	// HyDEExpansion asks the model to write distinct hypothetical passages answering the query,
+	// which are then searched for in addition to the query (Hypothetical Document Embeddings).
+	HyDEExpansion
+)
+
+// LLMQueryExpander implements retriever.Expander with a language model.
+type LLMQueryExpander struct {
+	model *LanguageModel
+	mode  QueryExpansion
+	n     int
+}
+
+var _ retriever.Expander = (*LLMQueryExpander)(nil)
+
+type expandedQueries struct {
+	Queries []string `json:"queries" description:"alternative search queries"`
+}
+
+type hypotheticalPassages struct {
+	Passages []string `json:"passages" description:"distinct hypothetical passages"`
+}
+
+// NewLLMQueryExpander creates an expander producing n rewritten queries in the given mode.
+// The original query is always returned first, followed by the rewrites.
+func NewLLMQueryExpander(model *LanguageModel, mode QueryExpansion, n int) *LLMQueryExpander {
+	if n <= 0 {
+		n = 3
+	}
+	return &LLMQueryExpander{model: model, mode: mode, n: n}
+}
+
+// Expand returns the original query followed by the model's distinct rewrites.
+func (e *LLMQueryExpander) Expand(ctx context.Context, query string) ([]string, error) {
+	var rewrites []string
+	switch e.mode {
+	case HyDEExpansion:
+		var out hypotheticalPassages
+		if err := e.model.clone().HumanPrompt(fmt.Sprintf(
+			"Write %d short passages that answer the following question as different reference documents would. "+
+				"Each passage should cover a different aspect or angle. "+
+				"Do not mention that the passages are hypothetical.\n\nQuestion: %s", e.n, query)).QWith(ctx, &out); err != nil {
+			return nil, err
+		}
+		rewrites = out.Passages
+	default:
+		var out expandedQueries
+		if err := e.model.clone().HumanPrompt(fmt.Sprintf(
+			"Generate %d different search queries that retrieve documents relevant to the question below. "+
+				"Vary the wording and perspective, and keep each query self-contained.\n\nQuestion: %s", e.n, query)).QWith(ctx, &out); err != nil {
+			return nil, err
+		}
+		rewrites = out.Queries
+	}
+
+	// duplicates would only weight the same ranking several times in the fusion
+	queries := []string{query}
+	seen := map[string]bool{query: true}
+	for _, q := range rewrites {
+		if q = strings.TrimSpace(q); q != "" && !seen[q] {
+			seen[q] = true
+			queries = append(queries, q)
+		}
+	}
+
+	if len(queries) > e.n+1 {
+		queries = queries[:e.n+1]
+	}
+	return queries, nil
+}
@@ -0,0 +1,57 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobenpark/gothought/vectorstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLLMReranker_Rerank(t *testing.T) {
+	provider := &fakeProvider{responses: []Message{
+		{Message: "```json\n{\"grades\": [{\"id\": 1, \"score\": 2}, {\"id\": 2, \"score\": 9}]}\n```"},
+		{Message: "```json\n{\"grades\": [{\"id\": 1, \"score\": 0}]}\n```"},
+	}}
+	model := NewLanguageModel(provider).SystemPrompt("You grade search results.")
+	reranker := NewLLMReranker(model, 2, 0.1)
+
+	results, err := reranker.Rerank(context.TODO(), "capital of France", []vectorstore.Result{
+		{Document: vectorstore.Document{ID: "a", Content: "Lyon is a city"}},
+		{Document: vectorstore.Document{ID: "b", Content: "Paris is the capital"}},
+		{Document: vectorstore.Document{ID: "c", Content: "Bananas"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, provider.calls)
+	require.Len(t, results, 2)
+	require.Equal(t, "b", results[0].ID)
+	require.InDelta(t, 0.9, results[0].Score, 1e-6)
+	require.Equal(t, "a", results[1].ID)
+
+	// the template model's history is left untouched
+	require.Len(t, model.messages, 1)
+	require.Equal(t, "system", provider.messages[0].Role)
+}
+
+func TestLLMQueryExpander_Expand(t *testing.T) {
+	provider := &fakeProvider{responses: []Message{
+		{Message: "```json\n{\"queries\": [\"French capital city\", \"capital of France\", \"seat of government France\"]}\n```"},
+	}}
+	expander := NewLLMQueryExpander(NewLanguageModel(provider), MultiQueryExpansion, 2)
+
+	queries, err := expander.Expand(context.TODO(), "capital of France")
+	require.NoError(t, err)
+	require.Equal(t, []string{"capital of France", "French capital city", "seat of government France"}, queries)
+}
+
+func TestLLMQueryExpander_HyDE(t *testing.T) {
+	provider := &fakeProvider{responses: []Message{
+		{Message: "```json\n{\"passages\": [\"Paris is the capital of France.\", \"Paris is the capital of France.\", \"The French government sits in Paris.\"]}\n```"},
+	}}
+	expander := NewLLMQueryExpander(NewLanguageModel(provider), HyDEExpansion, 3)
+
+	queries, err := expander.Expand(context.TODO(), "capital of France")
+	require.NoError(t, err)
+	require.Equal(t, 1, provider.calls)
+	require.Equal(t, []string{"capital of France", "Paris is the capital of France.", "The French government sits in Paris."}, queries)
+}
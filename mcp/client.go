@@ -0,0 +1,339 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Client is a connection to an MCP server.
+//
+//	client, err := mcp.Connect(ctx, mcp.NewStdioTransport("npx", "-y", "@modelcontextprotocol/server-everything"))
+//	if err != nil { ... }
+//	defer client.Close()
+//
+//	tools, err := client.Tools(ctx)
+//	for _, t := range tools {
+//		model.AddTool(t)
+//	}
+type Client struct {
+	transport Transport
+	info      Implementation
+
+	nextID  atomic.Int64
+	mu      sync.Mutex
+	pending map[string]chan *message
+	closed  bool
+	done    chan struct{}
+
+	serverInfo   Implementation
+	capabilities ServerCapabilities
+	instructions string
+}
+
+// ClientOption configures a Client.
+type ClientOption func(c *Client)
+
+// WithClientInfo sets the name and version the client reports to the server
+func WithClientInfo(name, version string) ClientOption {
+	return func(c *Client) {
+		c.info = Implementation{Name: name, Version: version}
+	}
+}
+
+// Connect starts transport and performs the MCP initialization handshake.
+func Connect(ctx context.Context, transport Transport, options ...ClientOption) (*Client, error) {
+	c := &Client{
+		transport: transport,
+		info:      Implementation{Name: "gothought", Version: "0.1.0"},
+		pending:   map[string]chan *message{},
+		done:      make(chan struct{}),
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	if err := transport.Start(ctx); err != nil {
+		return nil, err
+	}
+	go c.readLoop()
+
+	var result initializeResult
+	err := c.call(ctx, "initialize", initializeParams{
+		ProtocolVersion: ProtocolVersion,
+		Capabilities:    map[string]any{},
+		ClientInfo:      c.info,
+	}, &result)
+	if err != nil {
+		c.Close()
+		return nil, fmt.Errorf("mcp: initialize: %w", err)
+	}
+	c.serverInfo = result.ServerInfo
+	c.capabilities = result.Capabilities
+	c.instructions = result.Instructions
+
+	if err := c.notify(ctx, "notifications/initialized", nil); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// ServerInfo returns the name and version reported by the server.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// Capabilities returns the capabilities announced by the server.
+func (c *Client) Capabilities() ServerCapabilities {
+	return c.capabilities
+}
+
+// Instructions returns the usage hints the server sent during initialization, if any.
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// ListTools returns all tools offered by the server.
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	return paginate[Tool](ctx, c, "tools/list", "tools")
+}
+
+// CallTool calls the tool name with arguments, a JSON object.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	if len(arguments) == 0 {
+		arguments = json.RawMessage("{}")
+	}
+
+	var result CallToolResult
+	params := map[string]any{"name": name, "arguments": arguments}
+	if err := c.call(ctx, "tools/call", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListResources returns all resources offered by the server.
+func (c *Client) ListResources(ctx context.Context) ([]Resource, error) {
+	return paginate[Resource](ctx, c, "resources/list", "resources")
+}
+
+// ReadResource returns the contents of the resource at uri.
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	var result struct {
+		Contents []ResourceContents `json:"contents"`
+	}
+	if err := c.call(ctx, "resources/read", map[string]any{"uri": uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// ListPrompts returns all prompt templates offered by the server.
+func (c *Client) ListPrompts(ctx context.Context) ([]Prompt, error) {
+	return paginate[Prompt](ctx, c, "prompts/list", "prompts")
+}
+
+// GetPrompt renders the prompt name with arguments.
+func (c *Client) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*GetPromptResult, error) {
+	var result GetPromptResult
+	params := map[string]any{"name": name, "arguments": arguments}
+	if err := c.call(ctx, "prompts/get", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Ping checks that the server is responsive.
+func (c *Client) Ping(ctx context.Context) error {
+	return c.call(ctx, "ping", nil, nil)
+}
+
+// closeWait is how long Close waits for the transport to stop delivering messages.
+const closeWait = time.Second
+
+// Close ends the session and shuts down the transport; for stdio servers this stops the process.
+// Pending calls fail with ErrClosed. Close returns after at most a second even when the
+// transport keeps reading, e.g. an io.Reader passed to NewIOTransport that cannot be closed.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	c.mu.Unlock()
+
+	err := c.transport.Close()
+	select {
+	case <-c.done:
+	case <-time.After(closeWait):
+		c.failPending()
+	}
+	return err
+}
+
+// failPending marks the client closed and fails all pending calls with ErrClosed.
+func (c *Client) failPending() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	for id, response := range c.pending {
+		close(response)
+		delete(c.pending, id)
+	}
+}
+
+// paginate collects the items under key of every page of a list method.
+func paginate[T any](ctx context.Context, c *Client, method, key string) ([]T, error) {
+	var items []T
+	cursor := ""
+	for {
+		var params map[string]any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+
+		var page map[string]json.RawMessage
+		if err := c.call(ctx, method, params, &page); err != nil {
+			return nil, err
+		}
+
+		var batch []T
+		if raw, ok := page[key]; ok {
+			if err := json.Unmarshal(raw, &batch); err != nil {
+				return nil, fmt.Errorf("mcp: decoding %s: %w", method, err)
+			}
+		}
+		items = append(items, batch...)
+
+		cursor = ""
+		if raw, ok := page["nextCursor"]; ok {
+			_ = json.Unmarshal(raw, &cursor)
+		}
+		if cursor == "" {
+			return items, nil
+		}
+	}
+}
+
+// call sends a request and decodes the result of its response into result, which may be nil.
+func (c *Client) call(ctx context.Context, method string, params any, result any) error {
+	id := strconv.FormatInt(c.nextID.Add(1), 10)
+	msg, err := encode(message{JSONRPC: jsonrpcVersion, ID: json.RawMessage(id), Method: method}, params)
+	if err != nil {
+		return err
+	}
+
+	response := make(chan *message, 1)
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return ErrClosed
+	}
+	c.pending[id] = response
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	if err := c.transport.Send(ctx, msg); err != nil {
+		return err
+	}
+
+	select {
+	case res, ok := <-response:
+		if !ok {
+			return ErrClosed
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		if result == nil || len(res.Result) == 0 {
+			return nil
+		}
+		return json.Unmarshal(res.Result, result)
+	case <-ctx.Done():
+		_ = c.notify(context.Background(), "notifications/cancelled", map[string]any{
+			"requestId": json.RawMessage(id),
+			"reason":    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	}
+}
+
+func (c *Client) notify(ctx context.Context, method string, params any) error {
+	msg, err := encode(message{JSONRPC: jsonrpcVersion, Method: method}, params)
+	if err != nil {
+		return err
+	}
+	return c.transport.Send(ctx, msg)
+}
+
+// readLoop dispatches incoming messages until the transport closes.
+func (c *Client) readLoop() {
+	defer close(c.done)
+
+	for raw := range c.transport.Messages() {
+		var msg message
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			continue
+		}
+
+		switch {
+		case msg.isResponse():
+			// send under the lock, failPending may otherwise close the channel in between
+			c.mu.Lock()
+			if response, ok := c.pending[string(msg.ID)]; ok {
+				select {
+				case response <- &msg:
+				default: // duplicate response
+				}
+			}
+			c.mu.Unlock()
+		case msg.isRequest():
+			// answer asynchronously, a transport may deliver messages while sending
+			go c.answer(&msg)
+		}
+		// notifications such as logging or list changes are ignored
+	}
+
+	c.failPending()
+}
+
+// answer replies to requests sent by the server. Only ping is supported since
+// the client does not announce sampling, roots or elicitation capabilities.
+func (c *Client) answer(req *message) {
+	reply := message{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.Method == "ping" {
+		reply.Result = json.RawMessage("{}")
+	} else {
+		reply.Error = &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+
+	bt, err := json.Marshal(reply)
+	if err != nil {
+		return
+	}
+	_ = c.transport.Send(context.Background(), bt)
+}
+
+// encode marshals msg with params, leaving params out when nil.
+func encode(msg message, params any) ([]byte, error) {
+	if params != nil {
+		bt, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = bt
+	}
+	return json.Marshal(msg)
+}
@@ -0,0 +1,153 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/stretchr/testify/require"
+)
+
+var testServer string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "mcp-testserver")
+	if err != nil {
+		panic(err)
+	}
+	testServer = filepath.Join(dir, "testserver")
+	if out, err := exec.Command("go", "build", "-o", testServer, "./internal/testserver").CombinedOutput(); err != nil {
+		panic(string(out))
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// startHTTPServer runs the test server in HTTP mode and returns its endpoint.
+func startHTTPServer(t *testing.T) string {
+	cmd := exec.Command(testServer, "-http", "127.0.0.1:0")
+	stdout, err := cmd.StdoutPipe()
+	require.NoError(t, err)
+	require.NoError(t, cmd.Start())
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+
+	endpoint, err := bufio.NewReader(stdout).ReadString('\n')
+	require.NoError(t, err)
+	return strings.TrimSpace(endpoint)
+}
+
+func TestClient(t *testing.T) {
+	transports := map[string]func(t *testing.T) Transport{
+		"stdio": func(t *testing.T) Transport { return NewStdioTransport(testServer) },
+		"http":  func(t *testing.T) Transport { return NewHTTPTransport(startHTTPServer(t)) },
+	}
+
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+			client, err := Connect(ctx, transport(t))
+			require.NoError(t, err)
+
+			require.Equal(t, "testserver", client.ServerInfo().Name)
+			require.Equal(t, "A server for tests.", client.Instructions())
+			require.NotNil(t, client.Capabilities().Tools)
+			require.NoError(t, client.Ping(ctx))
+
+			tools, err := client.Tools(ctx)
+			require.NoError(t, err)
+			require.Len(t, tools, 4)
+
+			byName := map[string]tool.Tool{}
+			for _, tl := range tools {
+				byName[tl.Name()] = tl
+			}
+			require.Equal(t, "Echoes the text", byName["echo"].Description())
+			require.Equal(t, "object", byName["echo"].ParameterSchema()["type"])
+
+			text, err := byName["echo"].Call(ctx, `{"text": "hello"}`)
+			require.NoError(t, err)
+			require.Equal(t, "hello", text)
+
+			res, err := tool.Call(ctx, byName["add"], `{"a": 2, "b": 3}`)
+			require.NoError(t, err)
+			require.Equal(t, "5", res.Text)
+			require.IsType(t, &CallToolResult{}, res.Metadata["mcp"])
+
+			res, err = tool.Call(ctx, byName["fail"], `{}`)
+			require.NoError(t, err)
+			require.True(t, res.IsError)
+			require.Equal(t, "Error: something went wrong", res.Content())
+
+			res, err = tool.Call(ctx, byName["image"], `{}`)
+			require.NoError(t, err)
+			require.Len(t, res.Artifacts, 1)
+			require.Equal(t, "image/png", res.Artifacts[0].MIMEType)
+			require.Equal(t, []byte("\x89PNG"), res.Artifacts[0].Data)
+
+			_, err = client.CallTool(ctx, "missing", nil)
+			var rpcErr *Error
+			require.ErrorAs(t, err, &rpcErr)
+			require.Equal(t, CodeInvalidParams, rpcErr.Code)
+
+			resources, err := client.ListResources(ctx)
+			require.NoError(t, err)
+			require.Len(t, resources, 1)
+			contents, err := client.ReadResource(ctx, resources[0].URI)
+			require.NoError(t, err)
+			require.Equal(t, "# Test server", contents[0].Text)
+
+			prompts, err := client.ListPrompts(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "greet", prompts[0].Name)
+			require.True(t, prompts[0].Arguments[0].Required)
+			prompt, err := client.GetPrompt(ctx, "greet", map[string]string{"name": "Ada"})
+			require.NoError(t, err)
+			require.Equal(t, "Say hello to Ada", prompt.Messages[0].Content.Text)
+
+			require.NoError(t, client.Close())
+			require.ErrorIs(t, client.Ping(ctx), ErrClosed)
+		})
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestClient_CloseIOTransport(t *testing.T) {
+	// the server never closes its output, as a host process that keeps running
+	connect := func(wrap func(io.Reader) io.Reader) *Client {
+		hostR, serverW := io.Pipe()
+		serverR, hostW := io.Pipe()
+		go func() {
+			_ = testTools().Serve(context.Background(), NewIOTransport(serverR, nopWriteCloser{serverW}))
+		}()
+		client, err := Connect(context.TODO(), NewIOTransport(wrap(hostR), hostW))
+		require.NoError(t, err)
+		return client
+	}
+
+	client := connect(func(r io.Reader) io.Reader { return r })
+	start := time.Now()
+	require.NoError(t, client.Close())
+	require.Less(t, time.Since(start), closeWait)
+
+	// a reader that cannot be closed delays Close by at most closeWait
+	client = connect(func(r io.Reader) io.Reader { return struct{ io.Reader }{r} })
+	start = time.Now()
+	require.NoError(t, client.Close())
+	require.Less(t, time.Since(start), 2*closeWait)
+	require.ErrorIs(t, client.Ping(context.TODO()), ErrClosed)
+}
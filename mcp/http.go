@@ -0,0 +1,241 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+const sessionHeader = "Mcp-Session-Id"
+
+// HTTPTransport connects to an MCP server using the streamable HTTP transport.
+// Every message is POSTed to the endpoint; the server answers with a JSON body
+// or a stream of server-sent events carrying one or more messages.
+type HTTPTransport struct {
+	endpoint string
+	client   *http.Client
+	header   http.Header
+
+	messages chan []byte
+	done     chan struct{}
+	wg       sync.WaitGroup
+	// ctx is cancelled by Close to abort requests in flight
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu        sync.Mutex
+	sessionID string
+	closed    bool
+}
+
+// HTTPOption configures an HTTPTransport.
+type HTTPOption func(t *HTTPTransport)
+
+// WithHTTPClient sends requests with client instead of http.DefaultClient
+func WithHTTPClient(client *http.Client) HTTPOption {
+	return func(t *HTTPTransport) {
+		t.client = client
+	}
+}
+
+// WithHeader adds a header to every request, e.g. for authorization
+func WithHeader(key, value string) HTTPOption {
+	return func(t *HTTPTransport) {
+		t.header.Add(key, value)
+	}
+}
+
+// NewHTTPTransport creates a transport for the MCP endpoint at url.
+func NewHTTPTransport(url string, options ...HTTPOption) *HTTPTransport {
+	t := &HTTPTransport{
+		endpoint: url,
+		client:   http.DefaultClient,
+		header:   http.Header{},
+		messages: make(chan []byte, 16),
+		done:     make(chan struct{}),
+	}
+	t.ctx, t.cancel = context.WithCancel(context.Background())
+	for _, option := range options {
+		option(t)
+	}
+	return t
+}
+
+// Start does nothing; the connection is established by the first request.
+func (t *HTTPTransport) Start(ctx context.Context) error {
+	return nil
+}
+
+// Send POSTs msg to the endpoint and delivers the messages of the response to Messages.
+func (t *HTTPTransport) Send(ctx context.Context, msg []byte) error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return ErrClosed
+	}
+	sessionID := t.sessionID
+	t.wg.Add(1)
+	t.mu.Unlock()
+	defer t.wg.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(t.ctx, cancel)
+	defer stop()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	t.setHeaders(request, sessionID)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Accept", "application/json, text/event-stream")
+
+	res, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return fmt.Errorf("mcp: server returned %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	if id := res.Header.Get(sessionHeader); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+
+	if res.StatusCode == http.StatusAccepted {
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type"))
+	switch mediaType {
+	case "text/event-stream":
+		return t.readEvents(res.Body)
+	case "application/json":
+		body, err := io.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return t.deliver(body)
+	default:
+		return nil
+	}
+}
+
+func (t *HTTPTransport) setHeaders(request *http.Request, sessionID string) {
+	for key, values := range t.header {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+	request.Header.Set("MCP-Protocol-Version", ProtocolVersion)
+	if sessionID != "" {
+		request.Header.Set(sessionHeader, sessionID)
+	}
+}
+
+// readEvents delivers the data of every server-sent event in r.
+func (t *HTTPTransport) readEvents(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if err := t.deliver([]byte(strings.Join(data, "\n"))); err != nil {
+					return err
+				}
+				data = data[:0]
+			}
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if len(data) > 0 {
+		if err := t.deliver([]byte(strings.Join(data, "\n"))); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// deliver passes a message, or each message of a batch, to Messages.
+func (t *HTTPTransport) deliver(body []byte) error {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+
+	msgs := [][]byte{body}
+	if body[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(body, &batch); err != nil {
+			return fmt.Errorf("mcp: invalid batch: %w", err)
+		}
+		msgs = msgs[:0]
+		for _, m := range batch {
+			msgs = append(msgs, m)
+		}
+	}
+
+	for _, m := range msgs {
+		select {
+		case t.messages <- m:
+		case <-t.done:
+			return ErrClosed
+		}
+	}
+	return nil
+}
+
+// Messages returns the messages received in responses to Send.
+func (t *HTTPTransport) Messages() <-chan []byte {
+	return t.messages
+}
+
+// Close terminates the session on the server and closes Messages.
+func (t *HTTPTransport) Close() error {
+	t.mu.Lock()
+	if t.closed {
+		t.mu.Unlock()
+		return nil
+	}
+	t.closed = true
+	sessionID := t.sessionID
+	t.mu.Unlock()
+
+	close(t.done)
+	t.cancel()
+	t.wg.Wait()
+	close(t.messages)
+
+	if sessionID == "" {
+		return nil
+	}
+	request, err := http.NewRequest(http.MethodDelete, t.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(request, sessionID)
+	res, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+	return nil
+}
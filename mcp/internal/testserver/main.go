@@ -0,0 +1,209 @@
+// Command testserver is a minimal MCP server used by the tests of package mcp.
+// It speaks stdio by default and streamable HTTP with -http, printing the
+// endpoint URL on the first line of its standard output.
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+)
+
+type message struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  any             `json:"result,omitempty"`
+	Error   any             `json:"error,omitempty"`
+}
+
+func main() {
+	addr := flag.String("http", "", "serve streamable HTTP on this address instead of stdio")
+	flag.Parse()
+
+	if *addr != "" {
+		serveHTTP(*addr)
+		return
+	}
+
+	scanner := bufio.NewScanner(os.Stdin)
+	out := json.NewEncoder(os.Stdout)
+	for scanner.Scan() {
+		var req message
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			continue
+		}
+		if res := handle(req); res != nil {
+			_ = out.Encode(res)
+		}
+	}
+}
+
+func serveHTTP(addr string) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("http://%s/mcp\n", listener.Addr())
+
+	http.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			if r.Header.Get("Mcp-Session-Id") != "session-1" {
+				http.Error(w, "unknown session", http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case http.MethodPost:
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var req message
+		if err := json.Unmarshal(body, &req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if req.Method == "initialize" {
+			w.Header().Set("Mcp-Session-Id", "session-1")
+		} else if r.Header.Get("Mcp-Session-Id") != "session-1" {
+			http.Error(w, "missing session", http.StatusBadRequest)
+			return
+		}
+
+		res := handle(req)
+		if res == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		bt, _ := json.Marshal(res)
+		// answer tool calls as an event stream to exercise both response kinds
+		if req.Method == "tools/call" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", bt)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(bt)
+	})
+	log.Fatal(http.Serve(listener, nil))
+}
+
+// handle returns the response to req, or nil for notifications.
+func handle(req message) *message {
+	if len(req.ID) == 0 {
+		return nil
+	}
+	res := &message{JSONRPC: "2.0", ID: req.ID}
+
+	var params struct {
+		Name      string          `json:"name"`
+		URI       string          `json:"uri"`
+		Cursor    string          `json:"cursor"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	_ = json.Unmarshal(req.Params, &params)
+
+	switch req.Method {
+	case "initialize":
+		res.Result = map[string]any{
+			"protocolVersion": "2025-06-18",
+			"capabilities":    map[string]any{"tools": map[string]any{}, "resources": map[string]any{}, "prompts": map[string]any{}},
+			"serverInfo":      map[string]any{"name": "testserver", "version": "1.0.0"},
+			"instructions":    "A server for tests.",
+		}
+	case "ping":
+		res.Result = map[string]any{}
+	case "tools/list":
+		// two pages to exercise pagination
+		if params.Cursor == "" {
+			res.Result = map[string]any{"tools": []any{
+				tool("echo", "Echoes the text", map[string]any{"text": map[string]any{"type": "string"}}),
+				tool("add", "Adds two numbers", map[string]any{"a": map[string]any{"type": "number"}, "b": map[string]any{"type": "number"}}),
+			}, "nextCursor": "page-2"}
+		} else {
+			res.Result = map[string]any{"tools": []any{
+				tool("fail", "Always fails", map[string]any{}),
+				tool("image", "Returns a pixel", map[string]any{}),
+			}}
+		}
+	case "tools/call":
+		res.Result = callTool(params.Name, params.Arguments)
+		if res.Result == nil {
+			res.Error = map[string]any{"code": -32602, "message": "unknown tool: " + params.Name}
+		}
+	case "resources/list":
+		res.Result = map[string]any{"resources": []any{
+			map[string]any{"uri": "file:///readme.md", "name": "readme.md", "mimeType": "text/markdown"},
+		}}
+	case "resources/read":
+		res.Result = map[string]any{"contents": []any{
+			map[string]any{"uri": params.URI, "mimeType": "text/markdown", "text": "# Test server"},
+		}}
+	case "prompts/list":
+		res.Result = map[string]any{"prompts": []any{
+			map[string]any{"name": "greet", "description": "Greets someone", "arguments": []any{
+				map[string]any{"name": "name", "required": true},
+			}},
+		}}
+	case "prompts/get":
+		var args struct {
+			Arguments map[string]string `json:"arguments"`
+		}
+		_ = json.Unmarshal(req.Params, &args)
+		res.Result = map[string]any{"messages": []any{
+			map[string]any{"role": "user", "content": map[string]any{"type": "text", "text": "Say hello to " + args.Arguments["name"]}},
+		}}
+	default:
+		res.Error = map[string]any{"code": -32601, "message": "method not found: " + req.Method}
+	}
+	return res
+}
+
+func tool(name, description string, properties map[string]any) map[string]any {
+	return map[string]any{
+		"name":        name,
+		"description": description,
+		"inputSchema": map[string]any{"type": "object", "properties": properties},
+	}
+}
+
+func callTool(name string, arguments json.RawMessage) any {
+	var args struct {
+		Text string  `json:"text"`
+		A    float64 `json:"a"`
+		B    float64 `json:"b"`
+	}
+	_ = json.Unmarshal(arguments, &args)
+
+	text := func(s string) map[string]any { return map[string]any{"type": "text", "text": s} }
+	switch name {
+	case "echo":
+		return map[string]any{"content": []any{text(args.Text)}}
+	case "add":
+		sum := args.A + args.B
+		return map[string]any{
+			"content":           []any{text(fmt.Sprint(sum))},
+			"structuredContent": map[string]any{"sum": sum},
+		}
+	case "fail":
+		return map[string]any{"content": []any{text("something went wrong")}, "isError": true}
+	case "image":
+		pixel := base64.StdEncoding.EncodeToString([]byte("\x89PNG"))
+		return map[string]any{"content": []any{map[string]any{"type": "image", "data": pixel, "mimeType": "image/png"}}}
+	}
+	return nil
+}
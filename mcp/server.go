@@ -0,0 +1,431 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// Server publishes tools to MCP hosts.
+//
+//	server := mcp.NewServer("search", "1.0.0").
+//		AddTool(tool.NewBraveSearchTool(key)).
+//		AddTool(tool.NewWikipediaTool(3, "en"))
+//
+//	// run as a subprocess of the host
+//	err := server.ServeStdio(ctx)
+//
+//	// or over streamable HTTP
+//	http.Handle("/mcp", server)
+type Server struct {
+	info         Implementation
+	instructions string
+
+	mu    sync.RWMutex
+	tools map[string]tool.Tool
+	order []string
+
+	sessionMu sync.Mutex
+	// sessions maps session IDs to the time they were last used
+	sessions       map[string]time.Time
+	sessionTimeout time.Duration
+	maxSessions    int
+	now            func() time.Time
+}
+
+// ServerOption configures a Server.
+type ServerOption func(s *Server)
+
+// WithInstructions sets the usage hints sent to hosts during initialization
+func WithInstructions(instructions string) ServerOption {
+	return func(s *Server) {
+		s.instructions = instructions
+	}
+}
+
+// WithSessionTimeout HTTP sessions unused for longer than timeout expire, default 30 minutes
+func WithSessionTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.sessionTimeout = timeout
+	}
+}
+
+// WithMaxSessions maximum number of HTTP sessions, further initialize requests are rejected with 503 until one ends or expires, default 1000
+func WithMaxSessions(n int) ServerOption {
+	return func(s *Server) {
+		s.maxSessions = n
+	}
+}
+
+// NewServer creates a server reporting name and version to hosts.
+func NewServer(name, version string, options ...ServerOption) *Server {
+	s := &Server{
+		info:           Implementation{Name: name, Version: version},
+		tools:          map[string]tool.Tool{},
+		sessions:       map[string]time.Time{},
+		sessionTimeout: 30 * time.Minute,
+		maxSessions:    1000,
+		now:            time.Now,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// AddTool publishes t; a tool with the same name is replaced.
+func (s *Server) AddTool(t tool.Tool) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tools[t.Name()]; !ok {
+		s.order = append(s.order, t.Name())
+	}
+	s.tools[t.Name()] = t
+	return s
+}
+
+// ServeStdio serves a single host over the standard input and output of the process.
+func (s *Server) ServeStdio(ctx context.Context) error {
+	return s.Serve(ctx, NewIOTransport(os.Stdin, os.Stdout))
+}
+
+// Serve answers the messages received over transport until it closes or ctx is done.
+// Requests are handled concurrently, so a slow tool does not block pings or other calls.
+func (s *Server) Serve(ctx context.Context, transport Transport) error {
+	if err := transport.Start(ctx); err != nil {
+		return err
+	}
+	defer transport.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		inflight = map[string]context.CancelFunc{}
+	)
+	defer wg.Wait()
+
+	for {
+		var raw []byte
+		var ok bool
+		select {
+		case raw, ok = <-transport.Messages():
+			if !ok {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		var msg message
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			reply, _ := json.Marshal(errorResponse(json.RawMessage("null"), CodeParseError, err.Error()))
+			_ = transport.Send(ctx, reply)
+			continue
+		}
+
+		if msg.Method == "notifications/cancelled" {
+			var params struct {
+				RequestID json.RawMessage `json:"requestId"`
+			}
+			_ = json.Unmarshal(msg.Params, &params)
+			mu.Lock()
+			if cancelRequest, ok := inflight[string(params.RequestID)]; ok {
+				cancelRequest()
+			}
+			mu.Unlock()
+			continue
+		}
+		if !msg.isRequest() {
+			continue
+		}
+
+		reqCtx, cancelRequest := context.WithCancel(ctx)
+		mu.Lock()
+		inflight[string(msg.ID)] = cancelRequest
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() {
+				mu.Lock()
+				delete(inflight, string(msg.ID))
+				mu.Unlock()
+				cancelRequest()
+			}()
+
+			res := s.handle(reqCtx, &msg)
+			if reqCtx.Err() != nil {
+				// the host is no longer interested in the response
+				return
+			}
+			reply, err := json.Marshal(res)
+			if err != nil {
+				reply, _ = json.Marshal(errorResponse(msg.ID, CodeInternalError, err.Error()))
+			}
+			_ = transport.Send(ctx, reply)
+		}()
+	}
+}
+
+// ServeHTTP implements the streamable HTTP transport. Every POST carries one message;
+// requests are answered with a JSON body, notifications and responses with 202 Accepted.
+// Sessions are created on initialize and ended with DELETE or when unused for the session
+// timeout. While the maximum number of sessions is live, initialize fails with 503 Service
+// Unavailable, so new clients cannot push out the sessions of existing ones.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+	case http.MethodDelete:
+		if !s.endSession(r.Header.Get(sessionHeader)) {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		// the server never initiates messages, so there is no stream to GET
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 16<<20))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var msg message
+	if err := json.Unmarshal(body, &msg); err != nil {
+		writeJSON(w, errorResponse(json.RawMessage("null"), CodeParseError, err.Error()))
+		return
+	}
+
+	if msg.Method == "initialize" {
+		id, ok := s.newSession()
+		if !ok {
+			http.Error(w, "too many sessions", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set(sessionHeader, id)
+	} else if id := r.Header.Get(sessionHeader); id == "" {
+		http.Error(w, "missing "+sessionHeader+" header", http.StatusBadRequest)
+		return
+	} else if !s.hasSession(id) {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	if !msg.isRequest() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	writeJSON(w, s.handle(r.Context(), &msg))
+}
+
+func writeJSON(w http.ResponseWriter, msg *message) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(msg)
+}
+
+// newSession starts a session and returns its ID, or false when the maximum number of
+// sessions is live. Expired sessions are removed first to make room.
+func (s *Server) newSession() (string, bool) {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	id := hex.EncodeToString(b[:])
+
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+
+	now := s.now()
+	for existing, used := range s.sessions {
+		if s.expired(used, now) {
+			delete(s.sessions, existing)
+		}
+	}
+	if s.maxSessions > 0 && len(s.sessions) >= s.maxSessions {
+		return "", false
+	}
+
+	s.sessions[id] = now
+	return id, true
+}
+
+// hasSession reports whether the session exists and has not expired, marking it as used.
+func (s *Server) hasSession(id string) bool {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+
+	used, ok := s.sessions[id]
+	if !ok {
+		return false
+	}
+	now := s.now()
+	if s.expired(used, now) {
+		delete(s.sessions, id)
+		return false
+	}
+	s.sessions[id] = now
+	return true
+}
+
+func (s *Server) expired(used, now time.Time) bool {
+	return s.sessionTimeout > 0 && now.Sub(used) > s.sessionTimeout
+}
+
+func (s *Server) endSession(id string) bool {
+	s.sessionMu.Lock()
+	defer s.sessionMu.Unlock()
+	_, ok := s.sessions[id]
+	delete(s.sessions, id)
+	return ok
+}
+
+// handle returns the response to a request.
+func (s *Server) handle(ctx context.Context, req *message) *message {
+	var (
+		result any
+		err    *Error
+	)
+
+	switch req.Method {
+	case "initialize":
+		result = initializeResult{
+			ProtocolVersion: ProtocolVersion,
+			Capabilities:    ServerCapabilities{Tools: map[string]any{}},
+			ServerInfo:      s.info,
+			Instructions:    s.instructions,
+		}
+	case "ping":
+		result = struct{}{}
+	case "tools/list":
+		result = map[string]any{"tools": s.listTools()}
+	case "tools/call":
+		result, err = s.callTool(ctx, req.Params)
+	default:
+		err = &Error{Code: CodeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+
+	if err != nil {
+		return errorResponse(req.ID, err.Code, err.Message)
+	}
+	bt, merr := json.Marshal(result)
+	if merr != nil {
+		return errorResponse(req.ID, CodeInternalError, merr.Error())
+	}
+	return &message{JSONRPC: jsonrpcVersion, ID: req.ID, Result: bt}
+}
+
+func errorResponse(id json.RawMessage, code int, msg string) *message {
+	return &message{JSONRPC: jsonrpcVersion, ID: id, Error: &Error{Code: code, Message: msg}}
+}
+
+func (s *Server) listTools() []Tool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tools := make([]Tool, 0, len(s.order))
+	for _, name := range s.order {
+		t := s.tools[name]
+		schema := t.ParameterSchema()
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		tools = append(tools, Tool{Name: t.Name(), Description: t.Description(), InputSchema: schema})
+	}
+	return tools
+}
+
+// callTool runs a tool. Failures of the tool itself are reported in the result with
+// isError so the model can see them; only unknown tools are protocol errors.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (*CallToolResult, *Error) {
+	var call struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &call); err != nil {
+		return nil, &Error{Code: CodeInvalidParams, Message: err.Error()}
+	}
+
+	s.mu.RLock()
+	t, ok := s.tools[call.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, &Error{Code: CodeInvalidParams, Message: fmt.Sprintf("unknown tool: %s", call.Name)}
+	}
+
+	args := strings.TrimSpace(string(call.Arguments))
+	if args == "" || args == "null" {
+		args = "{}"
+	}
+
+	res, err := tool.Call(ctx, t, args)
+	if err != nil {
+		return &CallToolResult{Content: []Content{{Type: "text", Text: err.Error()}}, IsError: true}, nil
+	}
+	return toCallToolResult(res), nil
+}
+
+// toCallToolResult converts a tool result to MCP content. Metadata stays on the server.
+func toCallToolResult(res *tool.Result) *CallToolResult {
+	out := &CallToolResult{IsError: res.IsError, Content: []Content{}}
+	if res.Text != "" {
+		out.Content = append(out.Content, Content{Type: "text", Text: res.Text})
+	}
+
+	if res.JSON != nil {
+		if bt, err := json.Marshal(res.JSON); err == nil {
+			out.Content = append(out.Content, Content{Type: "text", Text: string(bt)})
+			// structured content must be an object
+			if trimmed := strings.TrimSpace(string(bt)); strings.HasPrefix(trimmed, "{") {
+				out.StructuredContent = json.RawMessage(bt)
+			}
+		}
+	}
+
+	for _, a := range res.Artifacts {
+		out.Content = append(out.Content, artifactContent(a))
+	}
+
+	if len(out.Content) == 0 {
+		out.Content = append(out.Content, Content{Type: "text", Text: ""})
+	}
+	return out
+}
+
+func artifactContent(a tool.Artifact) Content {
+	if len(a.Data) == 0 {
+		return Content{Type: "resource_link", URI: a.URI, Name: a.Name, MIMEType: a.MIMEType}
+	}
+
+	data := base64.StdEncoding.EncodeToString(a.Data)
+	switch {
+	case strings.HasPrefix(a.MIMEType, "image/"):
+		return Content{Type: "image", Data: data, MIMEType: a.MIMEType}
+	case strings.HasPrefix(a.MIMEType, "audio/"):
+		return Content{Type: "audio", Data: data, MIMEType: a.MIMEType}
+	}
+
+	uri := a.URI
+	if uri == "" {
+		uri = "artifact:///" + a.Name
+	}
+	return Content{Type: "resource", Resource: &ResourceContents{URI: uri, MIMEType: a.MIMEType, Blob: data}}
+}
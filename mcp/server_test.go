@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/stretchr/testify/require"
+)
+
+type addParams struct {
+	A float64 `json:"a" description:"first operand"`
+	B float64 `json:"b" description:"second operand"`
+}
+
+func testTools() *Server {
+	return NewServer("calculator", "1.0.0", WithInstructions("Use add for sums.")).
+		AddTool(tool.NewFunc("add", "Adds two numbers", func(ctx context.Context, p addParams) (*tool.Result, error) {
+			return tool.JSONResult(map[string]float64{"sum": p.A + p.B}), nil
+		})).
+		AddTool(tool.NewFunc("divide", "Divides two numbers", func(ctx context.Context, p addParams) (float64, error) {
+			if p.B == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return p.A / p.B, nil
+		})).
+		AddTool(tool.NewFunc("chart", "Draws a chart", func(ctx context.Context, p struct{}) (*tool.Result, error) {
+			return &tool.Result{
+				Text:      "chart attached",
+				Artifacts: []tool.Artifact{{Name: "chart.png", MIMEType: "image/png", Data: []byte("png")}},
+				Metadata:  map[string]any{"internal": true},
+			}, nil
+		}))
+}
+
+func TestServer(t *testing.T) {
+	transports := map[string]func(t *testing.T, server *Server) Transport{
+		"stdio": func(t *testing.T, server *Server) Transport {
+			hostR, serverW := io.Pipe()
+			serverR, hostW := io.Pipe()
+			go func() {
+				_ = server.Serve(context.Background(), NewIOTransport(serverR, serverW))
+			}()
+			return NewIOTransport(hostR, hostW)
+		},
+		"http": func(t *testing.T, server *Server) Transport {
+			ts := httptest.NewServer(server)
+			t.Cleanup(ts.Close)
+			return NewHTTPTransport(ts.URL)
+		},
+	}
+
+	for name, transport := range transports {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.TODO()
+			client, err := Connect(ctx, transport(t, testTools()))
+			require.NoError(t, err)
+			defer client.Close()
+
+			require.Equal(t, "calculator", client.ServerInfo().Name)
+			require.Equal(t, "Use add for sums.", client.Instructions())
+
+			tools, err := client.ListTools(ctx)
+			require.NoError(t, err)
+			require.Len(t, tools, 3)
+			require.Equal(t, "add", tools[0].Name)
+			require.Equal(t, "first operand", tools[0].InputSchema["properties"].(map[string]any)["a"].(map[string]any)["description"])
+
+			res, err := client.CallTool(ctx, "add", []byte(`{"a": 1, "b": 2}`))
+			require.NoError(t, err)
+			require.False(t, res.IsError)
+			require.Equal(t, `{"sum":3}`, res.Content[0].Text)
+			require.Equal(t, map[string]any{"sum": 3.0}, res.StructuredContent)
+
+			res, err = client.CallTool(ctx, "divide", []byte(`{"a": 1, "b": 0}`))
+			require.NoError(t, err)
+			require.True(t, res.IsError)
+			require.Equal(t, "division by zero", res.Content[0].Text)
+
+			res, err = client.CallTool(ctx, "chart", nil)
+			require.NoError(t, err)
+			require.Len(t, res.Content, 2)
+			require.Equal(t, "image", res.Content[1].Type)
+			require.Nil(t, res.Meta)
+
+			_, err = client.CallTool(ctx, "missing", nil)
+			require.ErrorContains(t, err, "unknown tool")
+
+			_, err = client.ListPrompts(ctx)
+			var rpcErr *Error
+			require.ErrorAs(t, err, &rpcErr)
+			require.Equal(t, CodeMethodNotFound, rpcErr.Code)
+		})
+	}
+}
+
+func TestServer_HTTPSession(t *testing.T) {
+	ts := httptest.NewServer(testTools())
+	defer ts.Close()
+
+	ctx := context.TODO()
+	transport := NewHTTPTransport(ts.URL)
+	client, err := Connect(ctx, transport)
+	require.NoError(t, err)
+
+	// requests without a session are rejected
+	ping := `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(ping))
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusBadRequest, res.StatusCode)
+
+	// closing the client ends the session
+	sessionID := transport.sessionID
+	require.NoError(t, client.Close())
+
+	req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(ping))
+	require.NoError(t, err)
+	req.Header.Set(sessionHeader, sessionID)
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestServer_SessionLimits(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := NewServer("test", "1.0.0", WithSessionTimeout(time.Minute), WithMaxSessions(2))
+	server.now = func() time.Time { return now }
+
+	a, ok := server.newSession()
+	require.True(t, ok)
+	now = now.Add(30 * time.Second)
+	b, ok := server.newSession()
+	require.True(t, ok)
+
+	// live sessions are never pushed out by new ones
+	_, ok = server.newSession()
+	require.False(t, ok)
+	require.Len(t, server.sessions, 2)
+
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+	res, err := http.Post(ts.URL, "application/json", strings.NewReader(`{"jsonrpc": "2.0", "id": 1, "method": "initialize"}`))
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusServiceUnavailable, res.StatusCode)
+
+	// an idle session expires and makes room
+	now = now.Add(45 * time.Second)
+	require.True(t, server.hasSession(b))
+	c, ok := server.newSession()
+	require.True(t, ok)
+	require.False(t, server.hasSession(a))
+	require.True(t, server.hasSession(b))
+	require.True(t, server.hasSession(c))
+}
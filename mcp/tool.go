@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// RemoteTool adapts a tool of an MCP server to tool.Tool, so it can be registered
+// with LanguageModel.AddTool. Calls are forwarded to the server through the client.
+type RemoteTool struct {
+	client *Client
+	tool   Tool
+}
+
+// NewRemoteTool creates the adapter for t, a tool offered by the server behind client.
+func NewRemoteTool(client *Client, t Tool) *RemoteTool {
+	return &RemoteTool{client: client, tool: t}
+}
+
+// Tools lists the tools of the server and adapts each of them to tool.Tool.
+func (c *Client) Tools(ctx context.Context) ([]tool.Tool, error) {
+	tools, err := c.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	adapted := make([]tool.Tool, 0, len(tools))
+	for _, t := range tools {
+		adapted = append(adapted, NewRemoteTool(c, t))
+	}
+	return adapted, nil
+}
+
+// Name returns the name of the tool on the server
+func (r *RemoteTool) Name() string {
+	return r.tool.Name
+}
+
+// Description returns the description of the tool, falling back to its title
+func (r *RemoteTool) Description() string {
+	if r.tool.Description == "" {
+		return r.tool.Title
+	}
+	return r.tool.Description
+}
+
+// ParameterSchema returns the input schema declared by the server
+func (r *RemoteTool) ParameterSchema() map[string]interface{} {
+	if r.tool.InputSchema == nil {
+		return map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+	}
+	return r.tool.InputSchema
+}
+
+// Call calls the tool on the server and returns the content shown to the model
+func (r *RemoteTool) Call(ctx context.Context, params string) (string, error) {
+	res, err := r.CallResult(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return res.Content(), nil
+}
+
+// CallResult calls the tool on the server. Text content becomes the text of the result,
+// images, audio and binary resources become artifacts and structured content the JSON
+// payload. The raw CallToolResult is available in the "mcp" metadata.
+func (r *RemoteTool) CallResult(ctx context.Context, params string) (*tool.Result, error) {
+	res, err := r.client.CallTool(ctx, r.tool.Name, []byte(params))
+	if err != nil {
+		return nil, err
+	}
+	return convertResult(res), nil
+}
+
+func convertResult(res *CallToolResult) *tool.Result {
+	result := &tool.Result{
+		IsError:  res.IsError,
+		Metadata: map[string]any{"mcp": res},
+	}
+
+	var texts []string
+	for _, c := range res.Content {
+		switch c.Type {
+		case "text":
+			texts = append(texts, c.Text)
+		case "image", "audio":
+			data, err := base64.StdEncoding.DecodeString(c.Data)
+			if err != nil {
+				texts = append(texts, "(undecodable "+c.Type+" content)")
+				continue
+			}
+			result.Artifacts = append(result.Artifacts, tool.Artifact{MIMEType: c.MIMEType, Data: data})
+		case "resource_link":
+			result.Artifacts = append(result.Artifacts, tool.Artifact{Name: c.Name, MIMEType: c.MIMEType, URI: c.URI})
+		case "resource":
+			if c.Resource == nil {
+				continue
+			}
+			if c.Resource.Blob == "" {
+				texts = append(texts, c.Resource.Text)
+				continue
+			}
+			data, err := base64.StdEncoding.DecodeString(c.Resource.Blob)
+			if err != nil {
+				texts = append(texts, "(undecodable resource "+c.Resource.URI+")")
+				continue
+			}
+			result.Artifacts = append(result.Artifacts, tool.Artifact{MIMEType: c.Resource.MIMEType, Data: data, URI: c.Resource.URI})
+		}
+	}
+	result.Text = strings.Join(texts, "\n")
+
+	// the spec asks servers to mirror structured content as text, only add it when missing
+	if res.StructuredContent != nil && result.Text == "" {
+		result.JSON = res.StructuredContent
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned when using a transport or client that was closed.
+var ErrClosed = errors.New("mcp: connection closed")
+
+// Transport carries JSON-RPC messages between a client or server and its peer.
+type Transport interface {
+	// Start opens the connection. It is called once before any other method.
+	Start(ctx context.Context) error
+
+	// Send delivers a single encoded message to the peer.
+	Send(ctx context.Context, msg []byte) error
+
+	// Messages returns the channel of messages received from the peer.
+	// It is closed when the connection ends.
+	Messages() <-chan []byte
+
+	// Close ends the connection and releases its resources.
+	Close() error
+}
+
+// StdioTransport runs an MCP server as a subprocess and exchanges newline
+// delimited JSON-RPC messages over its standard input and output.
+type StdioTransport struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	stdout   io.Reader
+	messages chan []byte
+
+	mu     sync.Mutex
+	closed bool
+	exited chan struct{}
+	// closeTimeout is how long Close waits for the server to exit before killing it
+	closeTimeout time.Duration
+}
+
+// NewStdioTransport creates a transport that starts command with args.
+// The server's standard error is passed through to the standard error of this process.
+func NewStdioTransport(command string, args ...string) *StdioTransport {
+	cmd := exec.Command(command, args...)
+	cmd.Stderr = os.Stderr
+	return NewCommandTransport(cmd)
+}
+
+// NewCommandTransport creates a transport for a prepared command, which allows setting
+// its environment, working directory or standard error. Stdin and Stdout must be unset.
+func NewCommandTransport(cmd *exec.Cmd) *StdioTransport {
+	return &StdioTransport{
+		cmd:          cmd,
+		messages:     make(chan []byte, 16),
+		exited:       make(chan struct{}),
+		closeTimeout: 5 * time.Second,
+	}
+}
+
+// NewIOTransport creates a transport exchanging newline delimited messages over r and w,
+// e.g. the standard input and output of this process when it is itself run by an MCP host,
+// or pipes to a Server in the same process.
+func NewIOTransport(r io.Reader, w io.WriteCloser) *StdioTransport {
+	return &StdioTransport{
+		stdin:    w,
+		stdout:   r,
+		messages: make(chan []byte, 16),
+		exited:   make(chan struct{}),
+	}
+}
+
+// Start starts the server process and begins reading its output.
+func (s *StdioTransport) Start(ctx context.Context) error {
+	if s.cmd != nil {
+		stdin, err := s.cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stdout, err := s.cmd.StdoutPipe()
+		if err != nil {
+			return err
+		}
+		if err := s.cmd.Start(); err != nil {
+			return fmt.Errorf("mcp: starting server: %w", err)
+		}
+		s.stdin, s.stdout = stdin, stdout
+	}
+
+	go func() {
+		defer close(s.exited)
+		defer close(s.messages)
+
+		reader := bufio.NewReaderSize(s.stdout, 64*1024)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				s.messages <- line
+			}
+			if err != nil {
+				break
+			}
+		}
+		if s.cmd != nil {
+			_ = s.cmd.Wait()
+		}
+	}()
+	return nil
+}
+
+// Send writes msg as a single line to the server's standard input.
+func (s *StdioTransport) Send(ctx context.Context, msg []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrClosed
+	}
+	if _, err := s.stdin.Write(append(bytes.TrimSpace(msg), '\n')); err != nil {
+		return fmt.Errorf("mcp: writing to server: %w", err)
+	}
+	return nil
+}
+
+// Messages returns the messages written by the server to its standard output.
+func (s *StdioTransport) Messages() <-chan []byte {
+	return s.messages
+}
+
+// Close closes the server's standard input and waits for it to exit,
+// killing the process if it does not exit in time. Transports created with
+// NewIOTransport close their writer, and their reader if it is an io.Closer.
+func (s *StdioTransport) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	if s.stdin == nil {
+		return nil
+	}
+	err := s.stdin.Close()
+	if s.cmd == nil {
+		// unblock the pending read, the other side may never close its end
+		if closer, ok := s.stdout.(io.Closer); ok {
+			_ = closer.Close()
+		}
+		return err
+	}
+
+	select {
+	case <-s.exited:
+	case <-time.After(s.closeTimeout):
+		_ = s.cmd.Process.Kill()
+		<-s.exited
+	}
+	return err
+}
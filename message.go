@@ -1,10 +1,14 @@
 package gothought
 
+import "github.com/gobenpark/gothought/tool"
+
 type Message struct {
 	Role       string `json:"role"`
 	ToolCallID string `json:"tool_call_id"`
 	Message    string
 	ToolCalls  []ToolCalls `json:"tool_calls"`
+	// ToolResult is the full result behind a "tool" message; only its Content is sent to the model.
+	ToolResult *tool.Result `json:"-"`
 }
 
 type ResponseMessage struct {
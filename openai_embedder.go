@@ -0,0 +1,159 @@
+package gothought
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tidwall/gjson"
+)
+
+const (
+	// openAIMaxEmbeddingInputs is the maximum number of inputs accepted by a single embeddings request.
+	openAIMaxEmbeddingInputs = 2048
+	// openAIMaxEmbeddingTokens is the maximum number of tokens summed across all inputs of a request.
+	openAIMaxEmbeddingTokens = 300000
+)
+
+var openAIEmbeddingDimensions = map[string]int{
+	"text-embedding-3-small": 1536,
+	"text-embedding-3-large": 3072,
+	"text-embedding-ada-002": 1536,
+}
+
+type OpenAIEmbeddingBody struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+// OpenAIEmbedder implements embedding.Embedder using the OpenAI /v1/embeddings endpoint.
+// It shares the API key and endpoint of the OpenAIProvider it was created from.
+type OpenAIEmbedder struct {
+	provider   *OpenAIProvider
+	model      string
+	dimensions int
+}
+
+// NewOpenAIEmbedder creates an embedder for the given model using the credentials of p.
+// A positive dimensions value asks the API to shorten the vectors, which is supported by
+// the text-embedding-3 models; zero keeps the model's native size.
+func NewOpenAIEmbedder(p *OpenAIProvider, model string, dimensions int) *OpenAIEmbedder {
+	return &OpenAIEmbedder{provider: p, model: model, dimensions: dimensions}
+}
+
+// Dimensions returns the length of the vectors produced by the model.
+// It returns 0 when the size is neither configured nor known for the model.
+func (o *OpenAIEmbedder) Dimensions() int {
+	if o.dimensions > 0 {
+		return o.dimensions
+	}
+	return openAIEmbeddingDimensions[o.model]
+}
+
+// Model returns the name of the embedding model.
+func (o *OpenAIEmbedder) Model() string {
+	return o.model
+}
+
+// Embed returns the embeddings of texts, splitting them into as many requests as
+// needed to stay within the API's per-request input and token limits.
+func (o *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, batch := range embeddingBatches(texts, openAIMaxEmbeddingInputs, openAIMaxEmbeddingTokens) {
+		res, err := o.embedBatch(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, res...)
+	}
+	return vectors, nil
+}
+
+func (o *OpenAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	body := OpenAIEmbeddingBody{
+		Model:          o.model,
+		Input:          texts,
+		Dimensions:     o.dimensions,
+		EncodingFormat: "float",
+	}
+
+	bt, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, o.provider.baseURL+"/embeddings", bytes.NewReader(bt))
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+o.provider.apiKey)
+
+	res, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	bodyBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status code: %d, body: %s", res.StatusCode, string(bodyBytes))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, item := range gjson.GetBytes(bodyBytes, "data").Array() {
+		index := int(item.Get("index").Int())
+		if index < 0 || index >= len(vectors) {
+			return nil, fmt.Errorf("embedding index %d out of range", index)
+		}
+
+		values := item.Get("embedding").Array()
+		vector := make([]float32, len(values))
+		for i, v := range values {
+			vector[i] = float32(v.Float())
+		}
+		vectors[index] = vector
+	}
+
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("missing embedding for input %d", i)
+		}
+	}
+	return vectors, nil
+}
+
+// embeddingBatches splits texts into consecutive batches holding at most maxInputs texts
+// and approximately maxTokens tokens, estimating four characters per token.
+func embeddingBatches(texts []string, maxInputs, maxTokens int) [][]string {
+	var (
+		batches [][]string
+		start   int
+		tokens  int
+	)
+
+	for i, text := range texts {
+		estimate := len(text)/4 + 1
+		if i > start && (i-start >= maxInputs || tokens+estimate > maxTokens) {
+			batches = append(batches, texts[start:i])
+			start = i
+			tokens = 0
+		}
+		tokens += estimate
+	}
+
+	if start < len(texts) {
+		batches = append(batches, texts[start:])
+	}
+	return batches
+}
@@ -0,0 +1,50 @@
+package gothought
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbeddingBatches(t *testing.T) {
+	texts := []string{"a", "b", "c", "d", "e"}
+	require.Equal(t, [][]string{{"a", "b"}, {"c", "d"}, {"e"}}, embeddingBatches(texts, 2, 100))
+
+	long := strings.Repeat("x", 40)
+	require.Equal(t, [][]string{{long}, {long}, {"a"}}, embeddingBatches([]string{long, long, "a"}, 10, 11))
+	require.Nil(t, embeddingBatches(nil, 10, 10))
+}
+
+func TestOpenAIEmbedder_Embed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/embeddings", r.URL.Path)
+		require.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+
+		var body OpenAIEmbeddingBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "text-embedding-3-small", body.Model)
+
+		// answer in reverse order to make sure results are placed by index
+		var data []string
+		for i := len(body.Input) - 1; i >= 0; i-- {
+			data = append(data, fmt.Sprintf(`{"index":%d,"embedding":[%d,0.5]}`, i, len(body.Input[i])))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("gpt-4o", "key", 0)
+	provider.baseURL = server.URL
+	embedder := NewOpenAIEmbedder(provider, "text-embedding-3-small", 0)
+
+	vectors, err := embedder.Embed(context.TODO(), []string{"a", "bb", "ccc"})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 0.5}, {2, 0.5}, {3, 0.5}}, vectors)
+	require.Equal(t, 1536, embedder.Dimensions())
+}
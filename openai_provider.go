@@ -16,15 +16,17 @@ import (
 )
 
 type OpenAIBody struct {
-	Model            string                   `json:"model"`
-	Messages         []OpenAIMessage          `json:"messages"`
-	Temperature      float32                  `json:"temperature,omitempty"`
-	TopP             int                      `json:"top_p,omitempty"`
-	FrequencyPenalty float32                  `json:"frequency_penalty,omitempty"`
-	PresencePenalty  float32                  `json:"presence_penalty,omitempty"`
-	Stream           bool                     `json:"stream"`
-	Tools            []map[string]interface{} `json:"tools"`
-	ToolChoice       string                   `json:"tool_choice,omitempty"`
+	Model             string                   `json:"model"`
+	Messages          []OpenAIMessage          `json:"messages"`
+	Temperature       float32                  `json:"temperature,omitempty"`
+	TopP              int                      `json:"top_p,omitempty"`
+	FrequencyPenalty  float32                  `json:"frequency_penalty,omitempty"`
+	PresencePenalty   float32                  `json:"presence_penalty,omitempty"`
+	Stream            bool                     `json:"stream"`
+	Tools             []map[string]interface{} `json:"tools,omitempty"`
+	ToolChoice        interface{}              `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool                    `json:"parallel_tool_calls,omitempty"`
+	ResponseFormat    map[string]interface{}   `json:"response_format,omitempty"`
 }
 
 type OpenAIMessage struct {
@@ -78,14 +80,38 @@ type OpenAIResponse struct {
 	SystemFingerprint string `json:"system_fingerprint"`
 }
 
+const openAIBaseURL = "https://api.openai.com/v1"
+
 type OpenAIProvider struct {
-	model       string
-	apiKey      string
-	temperature float32
+	model            string
+	apiKey           string
+	temperature      float32
+	baseURL          string
+	structuredOutput bool
+	// toolChoice and parallelToolCalls are set on copies returned by WithToolChoice
+	toolChoice        ToolChoice
+	parallelToolCalls *bool
 }
 
 func NewOpenAIProvider(model string, apikey string, temperature float32) *OpenAIProvider {
-	return &OpenAIProvider{apiKey: apikey, temperature: temperature, model: model}
+	return &OpenAIProvider{apiKey: apikey, temperature: temperature, model: model, baseURL: openAIBaseURL, structuredOutput: supportsJSONSchema(model)}
+}
+
+// WithStructuredOutput enables or disables native structured outputs through
+// response_format. By default it is enabled for models known to support json_schema
+// response formats, such as gpt-4o and gpt-4.1; enable it for other compatible models.
+func (o *OpenAIProvider) WithStructuredOutput(enabled bool) *OpenAIProvider {
+	o.structuredOutput = enabled
+	return o
+}
+
+// WithToolChoice returns a copy of the provider sending choice as tool_choice and
+// parallel, unless nil, as parallel_tool_calls.
+func (o *OpenAIProvider) WithToolChoice(choice ToolChoice, parallel *bool) Provider {
+	c := *o
+	c.toolChoice = choice
+	c.parallelToolCalls = parallel
+	return &c
 }
 
 func (o *OpenAIProvider) generateBody(tools map[string]tool.Tool, messages []Message, stream bool) OpenAIBody {
@@ -118,20 +144,39 @@ func (o *OpenAIProvider) generateBody(tools map[string]tool.Tool, messages []Mes
 			},
 		}
 	})
-	body.ToolChoice = "auto"
+	// tool_choice and parallel_tool_calls are only accepted together with tools
+	if len(body.Tools) > 0 {
+		body.ToolChoice = openAIToolChoice(o.toolChoice)
+		body.ParallelToolCalls = o.parallelToolCalls
+	}
 	return body
 }
 
+func openAIToolChoice(choice ToolChoice) interface{} {
+	switch choice.Mode {
+	case ToolChoiceNone, ToolChoiceRequired:
+		return string(choice.Mode)
+	case ToolChoiceFunction:
+		return map[string]interface{}{
+			"type":     "function",
+			"function": map[string]interface{}{"name": choice.Name},
+		}
+	default:
+		return string(ToolChoiceAuto)
+	}
+}
+
 func (o *OpenAIProvider) Generate(ctx context.Context, tools map[string]tool.Tool, messages []Message) (*Message, string, error) {
-	body := o.generateBody(tools, messages, false)
+	return o.generate(ctx, o.generateBody(tools, messages, false))
+}
 
-	bt, err := json.MarshalIndent(body, "", "\t")
+func (o *OpenAIProvider) generate(ctx context.Context, body OpenAIBody) (*Message, string, error) {
+	bt, err := json.Marshal(body)
 	if err != nil {
 		return nil, "", err
 	}
-	fmt.Println(string(bt))
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.openai.com/v1/chat/completions", bytes.NewReader(bt))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(bt))
 	if err != nil {
 		return nil, "", err
 	}
@@ -143,6 +188,7 @@ func (o *OpenAIProvider) Generate(ctx context.Context, tools map[string]tool.Too
 	if err != nil {
 		return nil, "", err
 	}
+	defer res.Body.Close()
 
 	if res.StatusCode != 200 {
 		buf := bytes.Buffer{}
@@ -162,6 +208,9 @@ func (o *OpenAIProvider) Generate(ctx context.Context, tools map[string]tool.Too
 
 		switch choice.Get("finish_reason").String() {
 		case "stop":
+			if refusal := choice.Get("message.refusal").String(); refusal != "" {
+				return nil, "", fmt.Errorf("model refused to respond: %s", refusal)
+			}
 			return &Message{
 				Message: choice.Get("message.content").String(),
 			}, FinishReasonStop, nil
@@ -200,15 +249,18 @@ func (o *OpenAIProvider) Generate(ctx context.Context, tools map[string]tool.Too
 	return nil, "", nil
 }
 
-func (o *OpenAIProvider) GenerateStreaming(ctx context.Context, tools map[string]tool.Tool, messages []Message, callback func(message Message) error) error {
-	body := o.generateBody(tools, messages, true)
+// GenerateStreaming streams a text response without offering tools to the model.
+func (o *OpenAIProvider) GenerateStreaming(ctx context.Context, messages []Message, callback func(message Message) error) error {
+	return o.stream(ctx, o.generateBody(nil, messages, true), callback)
+}
 
+func (o *OpenAIProvider) stream(ctx context.Context, body OpenAIBody, callback func(message Message) error) error {
 	bt, err := json.Marshal(body)
 	if err != nil {
 		return err
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://api.openai.com/v1/chat/completions", bytes.NewReader(bt))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(bt))
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,222 @@
+package gothought
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gobenpark/gothought/jsonschema"
+	"github.com/gobenpark/gothought/tool"
+	"github.com/samber/lo"
+)
+
+// SupportsStructuredOutput reports whether response_format json_schema is enabled.
+func (o *OpenAIProvider) SupportsStructuredOutput() bool {
+	return o.structuredOutput
+}
+
+// jsonSchemaUnsupported are model prefixes without json_schema response formats, checked
+// before jsonSchemaModels because their names share prefixes with supported models.
+var jsonSchemaUnsupported = []string{"gpt-4o-2024-05-13", "gpt-4-", "o1-mini", "o1-preview"}
+
+// jsonSchemaModels are model prefixes with json_schema response formats.
+var jsonSchemaModels = []string{"gpt-4o", "gpt-4.1", "gpt-4.5", "gpt-5", "chatgpt-4o", "o1", "o3", "o4"}
+
+// supportsJSONSchema reports whether the OpenAI model accepts response_format json_schema,
+// including fine-tunes of such models.
+func supportsJSONSchema(model string) bool {
+	model = strings.TrimPrefix(model, "ft:")
+	for _, prefix := range jsonSchemaUnsupported {
+		if strings.HasPrefix(model, prefix) {
+			return false
+		}
+	}
+	for _, prefix := range jsonSchemaModels {
+		if strings.HasPrefix(model, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateStructured sends the conversation with response_format set to the given schema.
+// The schema is sent in strict mode when it can be expressed in the subset OpenAI supports
+// for strict decoding, and as a best-effort schema otherwise.
+func (o *OpenAIProvider) GenerateStructured(ctx context.Context, tools map[string]tool.Tool, messages []Message, format ResponseFormat) (*Message, string, error) {
+	body := o.generateBody(tools, messages, false)
+	body.ResponseFormat = responseFormat(format)
+	return o.generate(ctx, body)
+}
+
+// GenerateStructuredStreaming is the streaming variant of GenerateStructured.
+func (o *OpenAIProvider) GenerateStructuredStreaming(ctx context.Context, messages []Message, format ResponseFormat, callback func(Message) error) error {
+	body := o.generateBody(nil, messages, true)
+	body.ResponseFormat = responseFormat(format)
+	return o.stream(ctx, body, callback)
+}
+
+func responseFormat(format ResponseFormat) map[string]interface{} {
+	schema, strict := strictSchema(format.Schema)
+	if !strict {
+		schema = format.Schema
+	}
+
+	return map[string]interface{}{
+		"type": "json_schema",
+		"json_schema": map[string]interface{}{
+			"name":   format.Name,
+			"schema": schema,
+			"strict": strict,
+		},
+	}
+}
+
+// strictSchema converts s into the form required by OpenAI strict mode: the root is an
+// object, every object lists all of its properties as required and forbids additional
+// properties, and optional properties become nullable instead. It reports false when s
+// uses features strict mode cannot express, such as maps with arbitrary keys.
+func strictSchema(s *jsonschema.Schema) (*jsonschema.Schema, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	root := *s
+	root.Schema = ""
+	if root.Ref != "" && len(root.Defs) > 0 {
+		// strict mode needs an object at the root, so inline the referenced definition
+		def, ok := root.Defs[defName(root.Ref)]
+		if !ok {
+			return nil, false
+		}
+		defs := root.Defs
+		root = *def
+		root.Defs = defs
+	}
+	if root.Type != "object" {
+		return nil, false
+	}
+
+	out, ok := strictSubschema(&root)
+	if !ok {
+		return nil, false
+	}
+
+	if len(root.Defs) > 0 {
+		out.Defs = make(map[string]*jsonschema.Schema, len(root.Defs))
+		for name, def := range root.Defs {
+			converted, ok := strictSubschema(def)
+			if !ok {
+				return nil, false
+			}
+			out.Defs[name] = converted
+		}
+	}
+	return out, true
+}
+
+func strictSubschema(s *jsonschema.Schema) (*jsonschema.Schema, bool) {
+	out := *s
+	out.Schema = ""
+	out.Defs = nil
+	// default is not part of the strict subset
+	out.Default = nil
+
+	if len(out.AllOf) == 1 && out.Type == "" {
+		// a lone allOf only carries annotations next to a $ref
+		inner, ok := strictSubschema(out.AllOf[0])
+		if !ok {
+			return nil, false
+		}
+		inner.Description = out.Description
+		return inner, true
+	}
+	if len(out.AllOf) > 0 {
+		return nil, false
+	}
+
+	if out.Type == "object" {
+		if _, isMap := out.AdditionalProperties.(*jsonschema.Schema); isMap {
+			return nil, false
+		}
+		if len(out.Properties) == 0 {
+			// free-form objects cannot be expressed in strict mode
+			return nil, false
+		}
+
+		out.AdditionalProperties = false
+		out.Properties = make(map[string]*jsonschema.Schema, len(s.Properties))
+		out.Required = nil
+		for _, name := range sortedProperties(s) {
+			prop, ok := strictSubschema(s.Properties[name])
+			if !ok {
+				return nil, false
+			}
+			if !lo.Contains(s.Required, name) {
+				prop = nullable(prop)
+			}
+			out.Properties[name] = prop
+			out.Required = append(out.Required, name)
+		}
+		out.PropertyOrder = out.Required
+	} else if out.Type == "" && out.Ref == "" && len(out.AnyOf) == 0 && out.Enum == nil && out.Const == nil {
+		// unconstrained values such as interface{} fields are not allowed in strict mode
+		return nil, false
+	}
+
+	if out.Items != nil {
+		items, ok := strictSubschema(out.Items)
+		if !ok {
+			return nil, false
+		}
+		out.Items = items
+	}
+
+	if len(out.AnyOf) > 0 {
+		out.AnyOf = make([]*jsonschema.Schema, len(s.AnyOf))
+		for i, sub := range s.AnyOf {
+			converted, ok := strictSubschema(sub)
+			if !ok {
+				return nil, false
+			}
+			out.AnyOf[i] = converted
+		}
+	}
+	if len(out.OneOf) > 0 {
+		return nil, false
+	}
+	return &out, true
+}
+
+// nullable allows null in addition to the values accepted by s.
+func nullable(s *jsonschema.Schema) *jsonschema.Schema {
+	if s.Ref != "" || len(s.AnyOf) > 0 || s.Type == "" {
+		return &jsonschema.Schema{AnyOf: []*jsonschema.Schema{s, {Type: "null"}}}
+	}
+	out := *s
+	out.Nullable = true
+	if out.Enum != nil {
+		out.Enum = append(append([]any{}, out.Enum...), nil)
+	}
+	return &out
+}
+
+func sortedProperties(s *jsonschema.Schema) []string {
+	names := lo.Filter(s.PropertyOrder, func(name string, _ int) bool {
+		_, ok := s.Properties[name]
+		return ok
+	})
+
+	var rest []string
+	for name := range s.Properties {
+		if !lo.Contains(names, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
+func defName(ref string) string {
+	name, _ := strings.CutPrefix(ref, "#/$defs/")
+	return name
+}
@@ -0,0 +1,101 @@
+package gothought
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gobenpark/gothought/jsonschema"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type strictTestPerson struct {
+	Name     string             `json:"name" default:"anonymous"`
+	Nickname string             `json:"nickname,omitempty"`
+	Friends  []strictTestPerson `json:"friends,omitempty"`
+}
+
+func TestStrictSchema(t *testing.T) {
+	schema, ok := strictSchema(jsonschema.For[strictTestPerson]())
+	require.True(t, ok)
+
+	bt, err := json.Marshal(schema)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"additionalProperties": false,
+		"required": ["name", "nickname", "friends"],
+		"properties": {
+			"name": {"type": "string"},
+			"nickname": {"type": ["string", "null"]},
+			"friends": {"type": ["array", "null"], "items": {"$ref": "#/$defs/strictTestPerson"}}
+		},
+		"$defs": {
+			"strictTestPerson": {
+				"type": "object",
+				"additionalProperties": false,
+				"required": ["name", "nickname", "friends"],
+				"properties": {
+					"name": {"type": "string"},
+					"nickname": {"type": ["string", "null"]},
+					"friends": {"type": ["array", "null"], "items": {"$ref": "#/$defs/strictTestPerson"}}
+				}
+			}
+		}
+	}`, string(bt))
+
+	_, ok = strictSchema(jsonschema.For[map[string]int]())
+	require.False(t, ok)
+	_, ok = strictSchema(jsonschema.For[struct {
+		Labels map[string]string `json:"labels"`
+	}]())
+	require.False(t, ok)
+}
+
+func TestOpenAIProvider_QWithStructuredOutput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+
+		bt, _ := json.Marshal(body)
+		format := gjson.GetBytes(bt, "response_format")
+		require.Equal(t, "json_schema", format.Get("type").String())
+		require.Equal(t, "testCity", format.Get("json_schema.name").String())
+		require.True(t, format.Get("json_schema.strict").Bool())
+		require.NotContains(t, gjson.GetBytes(bt, "messages.0.content").String(), "```json")
+
+		content, _ := json.Marshal(`{"name": "Seoul", "population": 9400000}`)
+		fmt.Fprintf(w, `{"choices": [{"finish_reason": "stop", "message": {"role": "assistant", "content": %s}}]}`, content)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("gpt-4o", "key", 0)
+	provider.baseURL = server.URL
+
+	var city testCity
+	require.NoError(t, NewLanguageModel(provider).HumanPrompt("Largest city in Korea?").QWith(context.TODO(), &city))
+	require.Equal(t, testCity{Name: "Seoul", Population: 9400000}, city)
+}
+
+func TestSupportsJSONSchema(t *testing.T) {
+	for model, expected := range map[string]bool{
+		"gpt-4o":                            true,
+		"gpt-4o-mini":                       true,
+		"gpt-4o-2024-08-06":                 true,
+		"gpt-4.1-nano":                      true,
+		"o3-mini":                           true,
+		"ft:gpt-4o-mini-2024-07-18:acme::1": true,
+		"gpt-4o-2024-05-13":                 false,
+		"gpt-4":                             false,
+		"gpt-4-turbo":                       false,
+		"gpt-3.5-turbo":                     false,
+		"o1-mini":                           false,
+		"llama3":                            false,
+	} {
+		require.Equal(t, expected, NewOpenAIProvider(model, "key", 0).SupportsStructuredOutput(), model)
+	}
+}
@@ -0,0 +1,55 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Auth authenticates a request before it is sent.
+type Auth interface {
+	Apply(req *http.Request) error
+}
+
+// AuthFunc adapts a function to Auth, e.g. to fetch short-lived tokens.
+type AuthFunc func(req *http.Request) error
+
+// Apply calls f
+func (f AuthFunc) Apply(req *http.Request) error {
+	return f(req)
+}
+
+// BearerToken sends token in the Authorization header.
+func BearerToken(token string) Auth {
+	return AuthFunc(func(req *http.Request) error {
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+}
+
+// BasicAuth sends HTTP basic credentials.
+func BasicAuth(username, password string) Auth {
+	return AuthFunc(func(req *http.Request) error {
+		req.SetBasicAuth(username, password)
+		return nil
+	})
+}
+
+// APIKey sends an API key named name in the header, query or cookie, matching
+// the "apiKey" security scheme of OpenAPI.
+func APIKey(in, name, value string) Auth {
+	return AuthFunc(func(req *http.Request) error {
+		switch in {
+		case "header":
+			req.Header.Set(name, value)
+		case "query":
+			query := req.URL.Query()
+			query.Set(name, value)
+			req.URL.RawQuery = query.Encode()
+		case "cookie":
+			req.AddCookie(&http.Cookie{Name: name, Value: value})
+		default:
+			return fmt.Errorf("openapi: unsupported api key location %q", in)
+		}
+		return nil
+	})
+}
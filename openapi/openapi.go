@@ -0,0 +1,336 @@
+// Package openapi turns the operations of an OpenAPI 3 document into tools,
+// so an agent can call a REST API without hand-written tool implementations.
+//
+//	tools, err := openapi.LoadFile("petstore.yaml",
+//		openapi.WithBaseURL("https://petstore.internal"),
+//		openapi.WithAuth(openapi.BearerToken(token)))
+//	for _, t := range tools {
+//		model.AddTool(t)
+//	}
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gobenpark/gothought/tool"
+	"gopkg.in/yaml.v3"
+)
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+type config struct {
+	baseURL string
+	client  *http.Client
+	auth    Auth
+	header  http.Header
+	filter  func(op OperationInfo) bool
+}
+
+// Option configures the generated tools.
+type Option func(c *config)
+
+// WithBaseURL sends requests to url instead of the first server of the document
+func WithBaseURL(url string) Option {
+	return func(c *config) {
+		c.baseURL = url
+	}
+}
+
+// WithHTTPClient sends requests with client instead of http.DefaultClient
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithAuth authenticates every request with auth
+func WithAuth(auth Auth) Option {
+	return func(c *config) {
+		c.auth = auth
+	}
+}
+
+// WithHeader adds a header to every request
+func WithHeader(key, value string) Option {
+	return func(c *config) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithFilter only generates tools for the operations accepted by filter
+func WithFilter(filter func(op OperationInfo) bool) Option {
+	return func(c *config) {
+		c.filter = filter
+	}
+}
+
+// OperationInfo identifies an operation of the document, e.g. for WithFilter.
+type OperationInfo struct {
+	ID     string
+	Method string // upper case, e.g. "GET"
+	Path   string
+	Tags   []string
+}
+
+// LoadFile reads an OpenAPI document in JSON or YAML from path and returns a tool per operation.
+func LoadFile(path string, options ...Option) ([]tool.Tool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data, options...)
+}
+
+// Load parses an OpenAPI document in JSON or YAML and returns a tool per operation,
+// in path and method order.
+func Load(data []byte, options ...Option) ([]tool.Tool, error) {
+	cfg := &config{client: http.DefaultClient, header: http.Header{}}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("openapi: parsing document: %w", err)
+	}
+	doc, ok := normalize(raw).(map[string]any)
+	if !ok {
+		return nil, errors.New("openapi: document is not an object")
+	}
+
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("openapi: unsupported version %q, only OpenAPI 3 is supported", version)
+	}
+
+	baseURL := cfg.baseURL
+	if baseURL == "" {
+		baseURL = serverURL(doc)
+	}
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		return nil, fmt.Errorf("openapi: no absolute server URL in document (%q), use WithBaseURL", baseURL)
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	r := &resolver{doc: doc}
+	paths, _ := doc["paths"].(map[string]any)
+
+	var tools []tool.Tool
+	names := map[string]bool{}
+	for _, path := range sortedKeys(paths) {
+		item, _ := r.resolve(paths[path]).(map[string]any)
+		if item == nil {
+			continue
+		}
+
+		for _, method := range methods {
+			op, ok := item[method].(map[string]any)
+			if !ok {
+				continue
+			}
+
+			info := OperationInfo{Method: strings.ToUpper(method), Path: path}
+			info.ID, _ = op["operationId"].(string)
+			for _, tag := range asSlice(op["tags"]) {
+				if s, ok := tag.(string); ok {
+					info.Tags = append(info.Tags, s)
+				}
+			}
+			if cfg.filter != nil && !cfg.filter(info) {
+				continue
+			}
+
+			operation, err := newOperation(r, cfg, baseURL, info, item, op)
+			if err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", info.Method, path, err)
+			}
+			if names[operation.name] {
+				return nil, fmt.Errorf("openapi: duplicate tool name %q", operation.name)
+			}
+			names[operation.name] = true
+			tools = append(tools, operation)
+		}
+	}
+	return tools, nil
+}
+
+// serverURL returns the URL of the first server, with variables replaced by their defaults.
+func serverURL(doc map[string]any) string {
+	servers := asSlice(doc["servers"])
+	if len(servers) == 0 {
+		return ""
+	}
+	server, _ := servers[0].(map[string]any)
+	url, _ := server["url"].(string)
+
+	variables, _ := server["variables"].(map[string]any)
+	for name, v := range variables {
+		variable, _ := v.(map[string]any)
+		url = strings.ReplaceAll(url, "{"+name+"}", fmt.Sprint(variable["default"]))
+	}
+	return url
+}
+
+// resolver inlines local references of the document.
+type resolver struct {
+	doc map[string]any
+}
+
+// resolve follows v while it is a {"$ref": "#/..."} object.
+func (r *resolver) resolve(v any) any {
+	for i := 0; i < 32; i++ {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return v
+		}
+		ref, ok := m["$ref"].(string)
+		if !ok {
+			return v
+		}
+		v = r.lookup(ref)
+	}
+	return nil
+}
+
+func (r *resolver) lookup(ref string) any {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil
+	}
+
+	var v any = r.doc
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[token]
+	}
+	return v
+}
+
+// schema converts an OpenAPI schema object to JSON Schema: references are inlined,
+// recursive references are cut off, and the 3.0 nullable keyword becomes a type array.
+func (r *resolver) schema(v any, seen map[string]bool) map[string]any {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return map[string]any{}
+	}
+
+	if ref, ok := m["$ref"].(string); ok {
+		if seen[ref] {
+			return map[string]any{"type": "object", "description": "recursive " + ref}
+		}
+		seen[ref] = true
+		defer delete(seen, ref)
+		return r.schema(r.lookup(ref), seen)
+	}
+
+	out := make(map[string]any, len(m))
+	for key, value := range m {
+		switch key {
+		case "properties", "patternProperties":
+			props, _ := value.(map[string]any)
+			converted := make(map[string]any, len(props))
+			for name, prop := range props {
+				converted[name] = r.schema(prop, seen)
+			}
+			out[key] = converted
+		case "items", "not":
+			out[key] = r.schema(value, seen)
+		case "additionalProperties":
+			if _, ok := value.(bool); ok {
+				out[key] = value
+			} else {
+				out[key] = r.schema(value, seen)
+			}
+		case "allOf", "anyOf", "oneOf":
+			var converted []any
+			for _, s := range asSlice(value) {
+				converted = append(converted, r.schema(s, seen))
+			}
+			out[key] = converted
+		case "nullable", "example", "xml", "externalDocs", "discriminator", "readOnly", "writeOnly", "deprecated":
+			// OpenAPI specific keywords
+		default:
+			out[key] = value
+		}
+	}
+
+	if nullable, _ := m["nullable"].(bool); nullable {
+		if typ, ok := out["type"].(string); ok {
+			out["type"] = []any{typ, "null"}
+		}
+	}
+	return out
+}
+
+// normalize converts the generic YAML values to their JSON equivalents.
+func normalize(v any) any {
+	switch x := v.(type) {
+	case map[string]any:
+		for key, value := range x {
+			x[key] = normalize(value)
+		}
+		return x
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for key, value := range x {
+			m[fmt.Sprint(key)] = normalize(value)
+		}
+		return m
+	case []any:
+		for i, value := range x {
+			x[i] = normalize(value)
+		}
+		return x
+	default:
+		return v
+	}
+}
+
+func asSlice(v any) []any {
+	s, _ := v.([]any)
+	return s
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// toolName derives a name accepted by LLM providers from the operation ID,
+// falling back to the method and path.
+func toolName(info OperationInfo) string {
+	name := info.ID
+	if name == "" {
+		name = strings.ToLower(info.Method) + info.Path
+	}
+	name = strings.Trim(invalidNameChars.ReplaceAllString(name, "_"), "_")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// jsonString is used to embed raw values in error messages.
+func jsonString(v any) string {
+	bt, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(bt)
+}
@@ -0,0 +1,237 @@
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/stretchr/testify/require"
+)
+
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Petstore
+  version: 1.0.0
+servers:
+  - url: https://{region}.petstore.example/v1
+    variables:
+      region:
+        default: eu
+paths:
+  /pets:
+    get:
+      operationId: listPets
+      summary: List pets
+      parameters:
+        - name: limit
+          in: query
+          description: How many pets to return
+          schema:
+            type: integer
+        - name: tags
+          in: query
+          schema:
+            type: array
+            items:
+              type: string
+      responses:
+        200:
+          description: A list of pets
+    post:
+      operationId: createPet
+      summary: Create a pet
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        201:
+          description: Created
+  /pets/{petId}:
+    parameters:
+      - $ref: '#/components/parameters/PetId'
+    get:
+      summary: Info for a pet
+      parameters:
+        - name: X-Request-Id
+          in: header
+          schema:
+            type: string
+      responses:
+        200:
+          description: A pet
+components:
+  parameters:
+    PetId:
+      name: petId
+      in: path
+      required: true
+      schema:
+        type: string
+  schemas:
+    Pet:
+      type: object
+      required: [name]
+      properties:
+        name:
+          type: string
+        tag:
+          type: string
+          nullable: true
+        parent:
+          $ref: '#/components/schemas/Pet'
+`
+
+func TestLoad(t *testing.T) {
+	tools, err := Load([]byte(petstore))
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+
+	names := []string{tools[0].Name(), tools[1].Name(), tools[2].Name()}
+	require.Equal(t, []string{"listPets", "createPet", "get_pets_petId"}, names)
+	require.Equal(t, "https://eu.petstore.example/v1", tools[0].(*Operation).baseURL)
+
+	schema, err := json.Marshal(tools[1].ParameterSchema())
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "object",
+		"required": ["body"],
+		"properties": {
+			"body": {
+				"type": "object",
+				"required": ["name"],
+				"properties": {
+					"name": {"type": "string"},
+					"tag": {"type": ["string", "null"]},
+					"parent": {"type": "object", "description": "recursive #/components/schemas/Pet"}
+				}
+			}
+		}
+	}`, string(schema))
+
+	require.Equal(t, []string{"petId"}, tools[2].ParameterSchema()["required"])
+
+	tools, err = Load([]byte(petstore), WithFilter(func(op OperationInfo) bool { return op.Method == "POST" }))
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+
+	_, err = Load([]byte(`{"swagger": "2.0"}`))
+	require.ErrorContains(t, err, "unsupported version")
+}
+
+func TestOperation_Call(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/v1/pets/missing" {
+			http.Error(w, `{"error": "not found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	tools, err := Load([]byte(petstore), WithBaseURL(server.URL+"/v1/"), WithAuth(BearerToken("secret")))
+	require.NoError(t, err)
+	ctx := context.TODO()
+
+	text, err := tools[0].Call(ctx, `{"limit": 10, "tags": ["cat", "dog"]}`)
+	require.NoError(t, err)
+	require.Equal(t, `{"ok": true}`, text)
+	require.Equal(t, http.MethodGet, got.Method)
+	require.Equal(t, "/v1/pets", got.URL.Path)
+	require.Equal(t, "10", got.URL.Query().Get("limit"))
+	require.Equal(t, []string{"cat", "dog"}, got.URL.Query()["tags"])
+	require.Equal(t, "Bearer secret", got.Header.Get("Authorization"))
+
+	_, err = tools[1].Call(ctx, `{"body": {"name": "Rex"}}`)
+	require.NoError(t, err)
+	require.Equal(t, http.MethodPost, got.Method)
+	require.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	require.JSONEq(t, `{"name": "Rex"}`, string(body))
+
+	res, err := tool.Call(ctx, tools[2], `{"petId": "a b", "X-Request-Id": "42"}`)
+	require.NoError(t, err)
+	require.Equal(t, "/v1/pets/a b", got.URL.Path)
+	require.Equal(t, "/v1/pets/a%20b", got.URL.EscapedPath())
+	require.Equal(t, "42", got.Header.Get("X-Request-Id"))
+	require.Equal(t, 200, res.Metadata["status"])
+
+	res, err = tool.Call(ctx, tools[2], `{"petId": "missing"}`)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+	require.Contains(t, res.Text, "404 Not Found")
+
+	_, err = tools[2].Call(ctx, `{}`)
+	require.ErrorContains(t, err, `missing required parameter "petId"`)
+}
+
+func TestAPIKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/items?page=2", nil)
+	require.NoError(t, APIKey("query", "api_key", "k").Apply(req))
+	require.Equal(t, "k", req.URL.Query().Get("api_key"))
+	require.Equal(t, "2", req.URL.Query().Get("page"))
+}
+
+const stylesDocument = `
+openapi: 3.0.3
+info: {title: Styles, version: 1.0.0}
+servers: [{url: https://api.example}]
+paths:
+  /items/{id}:
+    get:
+      operationId: listItems
+      parameters:
+        - {name: id, in: path, required: true, schema: {type: array, items: {type: string}}}
+        - {name: filter, in: query, style: deepObject, explode: true, schema: {type: object}}
+        - {name: fields, in: query, explode: false, schema: {type: array, items: {type: string}}}
+        - {name: point, in: query, explode: false, schema: {type: object}}
+        - {name: ids, in: query, style: pipeDelimited, explode: false, schema: {type: array, items: {type: integer}}}
+        - {name: page, in: query, schema: {type: object}}
+      responses:
+        200: {description: OK}
+`
+
+func TestOperation_Styles(t *testing.T) {
+	tools, err := Load([]byte(stylesDocument))
+	require.NoError(t, err)
+
+	req, err := tools[0].(*Operation).request(context.TODO(), map[string]any{
+		"id":     []any{"a", "b"},
+		"filter": map[string]any{"name": "x", "tag": "y"},
+		"fields": []any{"id", "name"},
+		"point":  map[string]any{"x": json.Number("1"), "y": json.Number("2")},
+		"ids":    []any{json.Number("3"), json.Number("4")},
+		"page":   map[string]any{"size": json.Number("10")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "/items/a,b", req.URL.Path)
+	require.Equal(t, url.Values{
+		"filter[name]": {"x"},
+		"filter[tag]":  {"y"},
+		"fields":       {"id,name"},
+		"point":        {"x,1,y,2"},
+		"ids":          {"3|4"},
+		"size":         {"10"},
+	}, req.URL.Query())
+
+	_, err = tools[0].(*Operation).request(context.TODO(), map[string]any{"id": "a", "ids": map[string]any{"a": "b"}})
+	require.ErrorContains(t, err, `objects cannot be sent with style "pipeDelimited"`)
+
+	_, err = Load([]byte(strings.Replace(stylesDocument, "style: deepObject", "style: matrix", 1)))
+	require.ErrorContains(t, err, `unsupported style "matrix" for query parameters`)
+
+	_, err = Load([]byte(strings.Replace(stylesDocument, "/items/{id}:", "/items/{id}/{missing}:", 1)))
+	require.ErrorContains(t, err, `path parameter "missing" is not defined`)
+}
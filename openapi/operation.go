@@ -0,0 +1,456 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// maxResponseSize limits how much of a response body is read
+const maxResponseSize = 1 << 20
+
+// parameter is a path, query, header or cookie parameter of an operation.
+type parameter struct {
+	name     string // name in the request
+	property string // name in the tool schema
+	in       string
+	required bool
+	schema   map[string]any
+	style    string // serialization style, see https://spec.openapis.org/oas/v3.0.3#style-values
+	explode  bool
+}
+
+// styles are the supported serialization styles by parameter location, the first one is the default.
+var styles = map[string][]string{
+	"path":   {"simple"},
+	"query":  {"form", "spaceDelimited", "pipeDelimited", "deepObject"},
+	"header": {"simple"},
+	"cookie": {"form"},
+}
+
+var pathTemplate = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Operation implements the Tool interface for a single operation of an OpenAPI document.
+// Parameters are exposed as properties of the tool schema and the JSON request body,
+// if any, as the "body" property. Parameters are sent in their declared style; the label
+// and matrix path styles are not supported.
+type Operation struct {
+	name        string
+	description string
+	method      string
+	path        string
+	baseURL     string
+	cfg         *config
+
+	parameters   []parameter
+	body         map[string]any // schema of the request body
+	bodyType     string         // media type of the request body, empty without body
+	bodyRequired bool
+}
+
+func newOperation(r *resolver, cfg *config, baseURL string, info OperationInfo, item, op map[string]any) (*Operation, error) {
+	o := &Operation{
+		name:    toolName(info),
+		method:  info.Method,
+		path:    info.Path,
+		baseURL: baseURL,
+		cfg:     cfg,
+	}
+
+	var parts []string
+	for _, key := range []string{"summary", "description"} {
+		if s, _ := op[key].(string); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, info.Method+" "+info.Path)
+	}
+	o.description = strings.Join(parts, "\n\n")
+
+	// operation parameters override path item parameters with the same name and location
+	params := map[string]map[string]any{}
+	var order []string
+	for _, list := range []any{item["parameters"], op["parameters"]} {
+		for _, p := range asSlice(list) {
+			param, _ := r.resolve(p).(map[string]any)
+			if param == nil {
+				return nil, fmt.Errorf("invalid parameter %s", jsonString(p))
+			}
+			key := fmt.Sprint(param["in"], ":", param["name"])
+			if _, ok := params[key]; !ok {
+				order = append(order, key)
+			}
+			params[key] = param
+		}
+	}
+
+	taken := map[string]bool{"body": true}
+	for _, key := range order {
+		param := params[key]
+		p := parameter{in: fmt.Sprint(param["in"])}
+		p.name, _ = param["name"].(string)
+		p.required, _ = param["required"].(bool)
+		if p.in == "path" {
+			p.required = true
+		}
+
+		supported, ok := styles[p.in]
+		if !ok {
+			return nil, fmt.Errorf("parameter %q: unsupported location %q", p.name, p.in)
+		}
+		p.style, _ = param["style"].(string)
+		if p.style == "" {
+			p.style = supported[0]
+		}
+		if !slices.Contains(supported, p.style) {
+			return nil, fmt.Errorf("parameter %q: unsupported style %q for %s parameters", p.name, p.style, p.in)
+		}
+		p.explode = p.style == "form"
+		if explode, ok := param["explode"].(bool); ok {
+			p.explode = explode
+		}
+
+		if s, ok := param["schema"]; ok {
+			p.schema = r.schema(s, map[string]bool{})
+		} else if content, ok := param["content"].(map[string]any); ok {
+			for _, mediaType := range sortedKeys(content) {
+				media, _ := content[mediaType].(map[string]any)
+				p.schema = r.schema(media["schema"], map[string]bool{})
+				break
+			}
+		}
+		if p.schema == nil {
+			p.schema = map[string]any{"type": "string"}
+		}
+		if description, _ := param["description"].(string); description != "" {
+			p.schema["description"] = description
+		}
+
+		p.property = p.name
+		if taken[p.property] {
+			p.property = p.in + "_" + p.name
+		}
+		taken[p.property] = true
+		o.parameters = append(o.parameters, p)
+	}
+
+	for _, match := range pathTemplate.FindAllStringSubmatch(info.Path, -1) {
+		if !slices.ContainsFunc(o.parameters, func(p parameter) bool { return p.in == "path" && p.name == match[1] }) {
+			return nil, fmt.Errorf("path parameter %q is not defined", match[1])
+		}
+	}
+
+	if rb, ok := op["requestBody"]; ok {
+		requestBody, _ := r.resolve(rb).(map[string]any)
+		content, _ := requestBody["content"].(map[string]any)
+		mediaType := pickMediaType(content)
+		if mediaType != "" {
+			media, _ := content[mediaType].(map[string]any)
+			o.body = r.schema(media["schema"], map[string]bool{})
+			if description, _ := requestBody["description"].(string); description != "" {
+				o.body["description"] = description
+			}
+			o.bodyType = mediaType
+			o.bodyRequired, _ = requestBody["required"].(bool)
+		}
+	}
+	return o, nil
+}
+
+// pickMediaType selects the request body representation, preferring JSON.
+func pickMediaType(content map[string]any) string {
+	keys := sortedKeys(content)
+	for _, key := range keys {
+		if key == "application/json" || strings.HasSuffix(key, "+json") {
+			return key
+		}
+	}
+	for _, key := range keys {
+		if key == "application/x-www-form-urlencoded" || strings.HasPrefix(key, "text/") {
+			return key
+		}
+	}
+	return ""
+}
+
+// Name returns the operation ID, or the method and path when the operation has none
+func (o *Operation) Name() string {
+	return o.name
+}
+
+// Description returns the summary and description of the operation
+func (o *Operation) Description() string {
+	return o.description
+}
+
+// Method returns the HTTP method of the operation
+func (o *Operation) Method() string {
+	return o.method
+}
+
+// Path returns the path template of the operation
+func (o *Operation) Path() string {
+	return o.path
+}
+
+// ParameterSchema merges the parameters and the request body of the operation into one object schema
+func (o *Operation) ParameterSchema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, p := range o.parameters {
+		properties[p.property] = p.schema
+		if p.required {
+			required = append(required, p.property)
+		}
+	}
+
+	if o.bodyType != "" {
+		properties["body"] = o.body
+		if o.bodyRequired {
+			required = append(required, "body")
+		}
+	}
+	sort.Strings(required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// Call sends the request and returns the response body
+func (o *Operation) Call(ctx context.Context, params string) (string, error) {
+	res, err := o.CallResult(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return res.Content(), nil
+}
+
+// CallResult sends the request. Responses with a status of 400 or above are returned as
+// error results so the model can correct its call; the "status" and "header" metadata
+// hold the status code and the response headers.
+func (o *Operation) CallResult(ctx context.Context, params string) (*tool.Result, error) {
+	args := map[string]any{}
+	if strings.TrimSpace(params) != "" {
+		dec := json.NewDecoder(strings.NewReader(params))
+		dec.UseNumber()
+		if err := dec.Decode(&args); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %v", err)
+		}
+	}
+
+	req, err := o.request(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := o.cfg.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxResponseSize))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &tool.Result{
+		Text:     string(body),
+		Metadata: map[string]any{"status": res.StatusCode, "header": res.Header},
+	}
+	if res.StatusCode >= 400 {
+		result.IsError = true
+		result.Text = fmt.Sprintf("HTTP %s: %s", res.Status, body)
+	}
+	return result, nil
+}
+
+// request builds the HTTP request for args.
+func (o *Operation) request(ctx context.Context, args map[string]any) (*http.Request, error) {
+	path := o.path
+	query := url.Values{}
+	header := http.Header{}
+	var cookies []*http.Cookie
+
+	for _, p := range o.parameters {
+		v, ok := args[p.property]
+		if !ok || v == nil {
+			if p.required {
+				return nil, fmt.Errorf("missing required parameter %q", p.property)
+			}
+			continue
+		}
+
+		switch p.in {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+p.name+"}", url.PathEscape(simpleValue(v, p.explode)))
+		case "query":
+			if err := p.addQuery(query, v); err != nil {
+				return nil, err
+			}
+		case "header":
+			header.Set(p.name, simpleValue(v, p.explode))
+		case "cookie":
+			cookies = append(cookies, &http.Cookie{Name: p.name, Value: strings.Join(formatValues(v), ",")})
+		}
+	}
+
+	var body io.Reader
+	if o.bodyType != "" {
+		if v, ok := args["body"]; ok && v != nil {
+			encoded, err := encodeBody(o.bodyType, v)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(encoded)
+		} else if o.bodyRequired {
+			return nil, fmt.Errorf("missing required parameter %q", "body")
+		}
+	}
+
+	target := o.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, o.method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range o.cfg.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", o.bodyType)
+	}
+	req.Header.Set("Accept", "application/json, */*;q=0.8")
+
+	if o.cfg.auth != nil {
+		if err := o.cfg.auth.Apply(req); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func encodeBody(mediaType string, v any) ([]byte, error) {
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("form body must be an object, got %s", jsonString(v))
+		}
+		form := url.Values{}
+		for key, value := range obj {
+			form[key] = formatValues(value)
+		}
+		return []byte(form.Encode()), nil
+	case strings.HasPrefix(mediaType, "text/"):
+		return []byte(strings.Join(formatValues(v), ",")), nil
+	default:
+		return json.Marshal(v)
+	}
+}
+
+// addQuery adds v to query in the style of the parameter.
+func (p parameter) addQuery(query url.Values, v any) error {
+	switch x := v.(type) {
+	case map[string]any:
+		keys := sortedKeys(x)
+		switch {
+		case p.style == "deepObject":
+			for _, key := range keys {
+				name := p.name + "[" + key + "]"
+				query[name] = append(query[name], formatValues(x[key])...)
+			}
+		case p.style == "form" && p.explode:
+			for _, key := range keys {
+				query[key] = append(query[key], formatValues(x[key])...)
+			}
+		case p.style == "form":
+			var pairs []string
+			for _, key := range keys {
+				pairs = append(pairs, key, strings.Join(formatValues(x[key]), ","))
+			}
+			query.Add(p.name, strings.Join(pairs, ","))
+		default:
+			return fmt.Errorf("parameter %q: objects cannot be sent with style %q", p.property, p.style)
+		}
+	case []any:
+		values := formatValues(x)
+		if p.explode {
+			query[p.name] = append(query[p.name], values...)
+			return nil
+		}
+		separator := map[string]string{"spaceDelimited": " ", "pipeDelimited": "|"}[p.style]
+		if separator == "" {
+			separator = ","
+		}
+		query.Add(p.name, strings.Join(values, separator))
+	default:
+		query[p.name] = append(query[p.name], formatValues(v)...)
+	}
+	return nil
+}
+
+// simpleValue renders a path or header parameter value in the simple style.
+func simpleValue(v any, explode bool) string {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return strings.Join(formatValues(v), ",")
+	}
+
+	var parts []string
+	for _, key := range sortedKeys(obj) {
+		value := strings.Join(formatValues(obj[key]), ",")
+		if explode {
+			parts = append(parts, key+"="+value)
+		} else {
+			parts = append(parts, key, value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatValues renders a parameter value in the default form and simple styles:
+// arrays become one value per item, objects and other values their JSON or plain text.
+func formatValues(v any) []string {
+	switch x := v.(type) {
+	case []any:
+		values := make([]string, 0, len(x))
+		for _, item := range x {
+			values = append(values, formatValues(item)...)
+		}
+		return values
+	case string:
+		return []string{x}
+	case json.Number:
+		return []string{x.String()}
+	case map[string]any:
+		return []string{jsonString(x)}
+	default:
+		return []string{fmt.Sprint(x)}
+	}
+}
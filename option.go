@@ -8,3 +8,66 @@ func WithIteration(iter int) Option {
 		c.maxIterations = iter
 	}
 }
+
+// WithCache responses of Q are looked up in and stored to the given cache
+func WithCache(cache Cache) Option {
+	return func(c *LanguageModel) {
+		c.cache = cache
+	}
+}
+
+// WithRepairAttempts number of times QWith sends invalid structured output back to the model for correction
+func WithRepairAttempts(n int) Option {
+	return func(c *LanguageModel) {
+		c.repairAttempts = n
+	}
+}
+
+// WithToolResultHandler handler receives every tool result, including metadata and artifacts hidden from the model
+func WithToolResultHandler(handler ToolResultHandler) Option {
+	return func(c *LanguageModel) {
+		c.toolResultHandler = handler
+	}
+}
+
+// WithToolSelector selector chooses the tools offered to the model on every query, e.g. NewEmbeddingToolSelector
+func WithToolSelector(selector ToolSelector) Option {
+	return func(c *LanguageModel) {
+		c.toolSelector = selector
+	}
+}
+
+// WithToolPolicy policy applies to every call of the named tool, replacing the default policy
+func WithToolPolicy(name string, policy ToolPolicy) Option {
+	return func(c *LanguageModel) {
+		c.toolPolicies[name] = policy
+	}
+}
+
+// WithDefaultToolPolicy policy applies to every tool without a policy of its own
+func WithDefaultToolPolicy(policy ToolPolicy) Option {
+	return func(c *LanguageModel) {
+		c.defaultToolPolicy = policy
+	}
+}
+
+// WithToolConcurrency number of tool calls of one model response executed at the same time, default 1
+func WithToolConcurrency(n int) Option {
+	return func(c *LanguageModel) {
+		c.toolConcurrency = n
+	}
+}
+
+// WithToolCache results of the named tools, or of all tools if none are named, are stored in cache and reused by later calls with the same arguments, e.g. NewMemoryToolCache
+func WithToolCache(cache ToolCache, tools ...string) Option {
+	return func(c *LanguageModel) {
+		c.toolCaching = &toolCaching{cache: cache, tools: cachedTools(tools)}
+	}
+}
+
+// WithRunToolCache results of the named tools, or of all tools if none are named, are reused by calls with the same arguments within one query
+func WithRunToolCache(tools ...string) Option {
+	return func(c *LanguageModel) {
+		c.toolCaching = &toolCaching{tools: cachedTools(tools)}
+	}
+}
@@ -3,72 +3,25 @@ package gothought
 import (
 	"encoding/json"
 	"errors"
-	"reflect"
+	"sort"
 	"strings"
+
+	"github.com/gobenpark/gothought/jsonschema"
 )
 
-type JSONSchema struct {
-	Type        string                `json:"type,omitempty"`
-	Description string                `json:"description,omitempty"`
-	Required    []string              `json:"required,omitempty"`
-	Properties  map[string]JSONSchema `json:"properties,omitempty"`
-}
+// JSONSchema is the schema type produced by GenerateJSONSchema.
+type JSONSchema = jsonschema.Schema
 
+// GenerateJSONSchema returns the JSON schema describing the encoding of v.
+// See jsonschema.ReflectType for the supported types and struct tags.
 func GenerateJSONSchema(v interface{}) JSONSchema {
-	t := reflect.TypeOf(v)
-
-	if t.Kind() == reflect.Ptr {
-		t = t.Elem()
-	}
-
-	schema := JSONSchema{
-		Type:       "Object",
-		Properties: make(map[string]JSONSchema),
-	}
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-
-		jsonTag := field.Tag.Get("json")
-		descriptionTag := field.Tag.Get("description")
-
-		if jsonTag == "" || jsonTag == "-" {
-			continue
-		}
-
-		jsonName := strings.Split(jsonTag, ",")[0]
-
-		var propertyType string
-		switch field.Type.Kind() {
-		case reflect.String:
-			propertyType = "string"
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
-			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			propertyType = "integer"
-		case reflect.Float32, reflect.Float64:
-			propertyType = "number"
-		case reflect.Bool:
-			propertyType = "boolean"
-		case reflect.Struct:
-			nestedSchema := GenerateJSONSchema(reflect.New(field.Type).Elem().Interface())
-			schema.Properties[jsonName] = nestedSchema
-			continue
-		case reflect.Slice, reflect.Array:
-			propertyType = "array"
-		}
-
-		schema.Properties[jsonName] = JSONSchema{
-			Type:        propertyType,
-			Description: descriptionTag,
-		}
-	}
-	return schema
+	return *jsonschema.Reflect(v)
 }
 
 func GenerateSchemaPrompt(v interface{}) string {
 	var st strings.Builder
 
-	st.WriteString("The output must be provided as a markdown code snippet, starting with ```json and ending with ```. Please generate JSON content that conforms to the JSON schema defined above:\n\n")
+	st.WriteString("The output must be provided as a markdown code snippet, starting with ```json and ending with ```. Please generate JSON content that conforms to the JSON schema defined below:\n\n")
 
 	result := GenerateJSONSchema(v)
 	bt, err := json.MarshalIndent(&result, "", "\t")
@@ -79,16 +32,162 @@ func GenerateSchemaPrompt(v interface{}) string {
 	return st.String()
 }
 
+// ParsePrompt extracts the JSON document from a model response with ExtractJSON and
+// decodes it into v.
 func ParsePrompt(v interface{}, text string) error {
-	_, halfJson, ok := strings.Cut(text, "```json")
-	if !ok {
-		return errors.New("no ```json at start of output")
+	jsonString, err := ExtractJSON(text)
+	if err != nil {
+		return err
 	}
+	return json.Unmarshal([]byte(jsonString), v)
+}
 
-	jsonString, _, ok := strings.Cut(halfJson, "```")
-	if !ok {
-		return errors.New("no ```json at end of output")
+// ExtractJSON finds the JSON document in a model response. It accepts a ```json fence,
+// a plain ``` fence or bare JSON surrounded by prose, and repairs the most common
+// mistakes of language models: trailing commas, and output cut off before the closing
+// fence, string, brackets or braces. When the text contains several JSON values, such
+// as a "[1]" reference in the prose before the document, the longest one is returned.
+func ExtractJSON(text string) (string, error) {
+	candidates, err := jsonCandidates(text)
+	if err != nil {
+		return "", err
 	}
+	return candidates[0], nil
+}
 
-	return json.Unmarshal([]byte(jsonString), v)
+// jsonCandidates returns every JSON value in text that is valid after repair, longest first.
+// Values nested inside another candidate are not returned separately.
+func jsonCandidates(text string) ([]string, error) {
+	candidate := text
+	if _, after, ok := strings.Cut(text, "```json"); ok {
+		candidate, _, _ = strings.Cut(after, "```")
+	} else if _, after, ok := strings.Cut(text, "```"); ok {
+		if inner, _, _ := strings.Cut(after, "```"); strings.ContainsAny(inner, "{[") {
+			candidate = inner
+		}
+	}
+
+	start := strings.IndexAny(candidate, "{[")
+	if start < 0 {
+		return nil, errors.New("no JSON found in output")
+	}
+
+	var candidates []string
+	for start >= 0 {
+		repaired, n := repairJSON(candidate[start:])
+		if json.Valid([]byte(repaired)) {
+			candidates = append(candidates, repaired)
+		} else {
+			n = 1
+		}
+		next := strings.IndexAny(candidate[start+n:], "{[")
+		if next < 0 {
+			break
+		}
+		start += n + next
+	}
+
+	if len(candidates) == 0 {
+		return nil, errors.New("output does not contain valid JSON")
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return len(candidates[i]) > len(candidates[j])
+	})
+	return candidates, nil
+}
+
+// repairJSON returns the first JSON value in s with trailing commas removed,
+// closing any string, array or object left open by truncation, and the number of
+// bytes of s it spans. A truncated object key is dropped together with its comma,
+// since it has no value yet.
+func repairJSON(s string) (string, int) {
+	var (
+		out        strings.Builder
+		stack      []byte
+		inString   bool
+		escaped    bool
+		expectKey  bool
+		pendingKey bool
+		keyStart   int
+	)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			out.WriteByte(c)
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+			if expectKey {
+				expectKey = false
+				pendingKey = true
+				keyStart = out.Len()
+			}
+		case ':':
+			pendingKey = false
+		case ',':
+			expectKey = len(stack) > 0 && stack[len(stack)-1] == '}'
+		case '{':
+			stack = append(stack, '}')
+			expectKey = true
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			trimTrailingComma(&out)
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			expectKey = false
+			out.WriteByte(c)
+			if len(stack) == 0 {
+				return out.String(), i + 1
+			}
+			continue
+		}
+		out.WriteByte(c)
+	}
+
+	// the output was truncated: close whatever is still open
+	if pendingKey {
+		truncated := out.String()[:keyStart]
+		out.Reset()
+		out.WriteString(truncated)
+		inString = false
+	}
+	if inString {
+		if escaped {
+			out.WriteByte('\\')
+		}
+		out.WriteByte('"')
+	}
+	trimTrailingComma(&out)
+	if trimmed := strings.TrimRight(out.String(), " \t\r\n"); strings.HasSuffix(trimmed, ":") {
+		out.Reset()
+		out.WriteString(trimmed)
+		out.WriteString("null")
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		out.WriteByte(stack[i])
+	}
+	return out.String(), len(s)
+}
+
+func trimTrailingComma(out *strings.Builder) {
+	trimmed := strings.TrimRight(out.String(), " \t\r\n")
+	if strings.HasSuffix(trimmed, ",") {
+		trimmed = trimmed[:len(trimmed)-1]
+		out.Reset()
+		out.WriteString(trimmed)
+	}
 }
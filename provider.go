@@ -5,6 +5,7 @@ package gothought
 import (
 	"context"
 
+	"github.com/gobenpark/gothought/jsonschema"
 	"github.com/gobenpark/gothought/tool"
 )
 
@@ -15,3 +16,35 @@ type Provider interface {
 type StreamingCapable interface {
 	GenerateStreaming(ctx context.Context, messages []Message, callback func(Message) error) error
 }
+
+// ResponseFormat describes the JSON document a structured response must conform to.
+type ResponseFormat struct {
+	// Name identifies the schema, using only letters, digits, underscores and dashes.
+	Name   string
+	Schema *jsonschema.Schema
+}
+
+// StructuredOutputCapable is implemented by providers that can constrain a response to a
+// JSON schema natively, such as OpenAI's response_format json_schema mode.
+type StructuredOutputCapable interface {
+	// SupportsStructuredOutput reports whether native structured output is available
+	// for the configured model.
+	SupportsStructuredOutput() bool
+
+	// GenerateStructured is like Provider.Generate, but a final response contains only
+	// a JSON document conforming to format.
+	GenerateStructured(ctx context.Context, tools map[string]tool.Tool, messages []Message, format ResponseFormat) (*Message, string, error)
+}
+
+// StructuredStreamingCapable is implemented by providers that can stream a response
+// constrained to a JSON schema.
+type StructuredStreamingCapable interface {
+	GenerateStructuredStreaming(ctx context.Context, messages []Message, format ResponseFormat, callback func(Message) error) error
+}
+
+// ToolChoiceCapable is implemented by providers that can constrain which tools the model
+// calls. WithToolChoice returns a provider applying choice, and parallel unless it is nil,
+// to every request it makes; the receiver itself is not modified.
+type ToolChoiceCapable interface {
+	WithToolChoice(choice ToolChoice, parallel *bool) Provider
+}
@@ -0,0 +1,177 @@
+package gothought
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/retriever"
+	"github.com/gobenpark/gothought/tool"
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// DefaultRAGTemplate is the prompt used to ask a question over retrieved context.
+// Templates receive the Question, the rendered Context and the raw Documents.
+const DefaultRAGTemplate = `Answer the question using only the numbered context passages below.
+Cite every passage you use with its number in square brackets, for example [1] or [2][3].
+If the context does not contain the answer, say that you don't know.
+
+Context:
+{{.Context}}
+Question: {{.Question}}`
+
+// RAGTemplateData is the data available to a RAG context template.
+type RAGTemplateData struct {
+	Question  string
+	Context   string
+	Documents []vectorstore.Result
+}
+
+// RAGAnswer is the result of a retrieval-augmented question.
+type RAGAnswer struct {
+	// Answer is the model's response, including its [n] citation markers.
+	Answer string `json:"answer"`
+	// Sources are the retrieved documents that the answer cites, by ascending citation number.
+	Sources []RAGSource `json:"sources"`
+	// Retrieved are all documents that were given to the model as context.
+	Retrieved []vectorstore.Result `json:"retrieved"`
+}
+
+// RAGSource is a retrieved document cited by an answer.
+type RAGSource struct {
+	// Citation is the number used for the document in the prompt and the answer.
+	Citation int                `json:"citation"`
+	Source   string             `json:"source"`
+	Document vectorstore.Result `json:"document"`
+}
+
+// RAGChain answers questions with a LanguageModel using documents retrieved from a vector store.
+type RAGChain struct {
+	model     *LanguageModel
+	retriever retriever.Retriever
+	topK      int
+	filter    vectorstore.Filter
+	template  string
+}
+
+type RAGOption func(r *RAGChain)
+
+// WithRAGTopK number of documents retrieved as context, default 4
+func WithRAGTopK(k int) RAGOption {
+	return func(r *RAGChain) {
+		r.topK = k
+	}
+}
+
+// WithRAGFilter metadata filter applied to every retrieval
+func WithRAGFilter(filter vectorstore.Filter) RAGOption {
+	return func(r *RAGChain) {
+		r.filter = filter
+	}
+}
+
+// WithRAGTemplate text/template used to build the question prompt, see DefaultRAGTemplate
+func WithRAGTemplate(tmpl string) RAGOption {
+	return func(r *RAGChain) {
+		r.template = tmpl
+	}
+}
+
+// NewRAGChain creates a RAGChain that retrieves from store using e and answers with model.
+func NewRAGChain(model *LanguageModel, e embedding.Embedder, store vectorstore.VectorStore, options ...RAGOption) *RAGChain {
+	return NewRAGChainWithRetriever(model, retriever.NewVector(e, store), options...)
+}
+
+// NewRAGChainWithRetriever creates a RAGChain that retrieves context with r, such as a
+// retriever.Hybrid combining keyword and vector search, and answers with model.
+func NewRAGChainWithRetriever(model *LanguageModel, r retriever.Retriever, options ...RAGOption) *RAGChain {
+	chain := &RAGChain{
+		model:     model,
+		retriever: r,
+		topK:      4,
+		template:  DefaultRAGTemplate,
+	}
+
+	for _, option := range options {
+		option(chain)
+	}
+
+	return chain
+}
+
+// Ask retrieves documents relevant to question, asks the model the question with its context
+// and returns the answer with the documents it cites. The model's conversation is sent as
+// history but not modified, so the retrieved context of one question is not sent again with the next.
+func (r *RAGChain) Ask(ctx context.Context, question string) (*RAGAnswer, error) {
+	tmpl, err := template.New("rag").Parse(r.template)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := r.retrieve(ctx, question)
+	if err != nil {
+		return nil, err
+	}
+
+	var prompt strings.Builder
+	if err := tmpl.Execute(&prompt, RAGTemplateData{
+		Question:  question,
+		Context:   tool.FormatChunks(docs),
+		Documents: docs,
+	}); err != nil {
+		return nil, err
+	}
+
+	response, err := r.model.clone().HumanPrompt(prompt.String()).Q(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &RAGAnswer{Answer: response.Message, Sources: []RAGSource{}, Retrieved: docs}
+	for _, n := range ParseCitations(response.Message) {
+		if n < 1 || n > len(docs) {
+			continue
+		}
+		answer.Sources = append(answer.Sources, RAGSource{
+			Citation: n,
+			Source:   tool.Source(docs[n-1].Document),
+			Document: docs[n-1],
+		})
+	}
+	return answer, nil
+}
+
+func (r *RAGChain) retrieve(ctx context.Context, question string) ([]vectorstore.Result, error) {
+	options := []vectorstore.SearchOption{vectorstore.WithTopK(r.topK)}
+	if len(r.filter) > 0 {
+		options = append(options, vectorstore.WithFilter(r.filter))
+	}
+	return r.retriever.Retrieve(ctx, question, options...)
+}
+
+var citationPattern = regexp.MustCompile(`\[(\d+(?:\s*,\s*\d+)*)\]`)
+
+// ParseCitations returns the distinct citation numbers referenced in text as [n] or [n, m],
+// in ascending order.
+func ParseCitations(text string) []int {
+	seen := map[int]bool{}
+	for _, match := range citationPattern.FindAllStringSubmatch(text, -1) {
+		for _, part := range strings.Split(match[1], ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(part))
+			if err == nil {
+				seen[n] = true
+			}
+		}
+	}
+
+	citations := make([]int, 0, len(seen))
+	for n := range seen {
+		citations = append(citations, n)
+	}
+	sort.Ints(citations)
+	return citations
+}
@@ -0,0 +1,53 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobenpark/gothought/document"
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/vectorstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCitations(t *testing.T) {
+	require.Equal(t, []int{1, 2, 3}, ParseCitations("A [2] and B [1, 3][2]."))
+	require.Empty(t, ParseCitations("no citations [x]"))
+}
+
+func TestRAGChain_Ask(t *testing.T) {
+	ctx := context.TODO()
+	embedder := embedding.NewHashEmbedder(128)
+	store := vectorstore.NewMemory(vectorstore.Cosine)
+	require.NoError(t, document.Ingest(ctx, embedder, store, []document.Document{
+		{ID: "paris", Content: "Paris is the capital of France.", Metadata: map[string]any{"source": "france.md"}},
+		{ID: "berlin", Content: "Berlin is the capital of Germany.", Metadata: map[string]any{"source": "germany.md"}},
+	}))
+
+	provider := &fakeProvider{responses: []Message{{Message: "The capital of France is Paris [1]. See also [7]."}}}
+	model := NewLanguageModel(provider).SystemPrompt("Answer briefly.")
+	chain := NewRAGChain(model, embedder, store,
+		WithRAGTopK(2),
+		WithRAGTemplate("{{.Context}}Q: {{.Question}}"),
+	)
+
+	answer, err := chain.Ask(ctx, "What is the capital of France?")
+	require.NoError(t, err)
+	require.Len(t, answer.Retrieved, 2)
+	require.Len(t, answer.Sources, 1)
+	require.Equal(t, 1, answer.Sources[0].Citation)
+	require.Equal(t, "france.md", answer.Sources[0].Source)
+	require.Equal(t, "paris", answer.Sources[0].Document.ID)
+
+	prompt := provider.messages[len(provider.messages)-1]
+	require.Equal(t, "user", prompt.Role)
+	require.Contains(t, prompt.Message, "[1] Source: france.md\nParis is the capital of France.")
+	require.Contains(t, prompt.Message, "Q: What is the capital of France?")
+
+	// the context of earlier questions is not sent again
+	_, err = chain.Ask(ctx, "What is the capital of Germany?")
+	require.NoError(t, err)
+	require.Len(t, provider.messages, 2)
+	require.Equal(t, "Answer briefly.", provider.messages[0].Message)
+	require.Len(t, model.messages, 1)
+}
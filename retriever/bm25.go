@@ -0,0 +1,183 @@
+package retriever
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// BM25 is an in-memory keyword index ranking documents with the Okapi BM25 function.
+// It complements vector search for queries that hinge on exact terms such as error
+// codes, identifiers or product names. Documents do not need vectors.
+type BM25 struct {
+	// K1 controls term frequency saturation, default 1.2.
+	K1 float64
+	// B controls document length normalisation, default 0.75.
+	B float64
+
+	mu       sync.RWMutex
+	docs     map[string]bm25Doc
+	postings map[string]map[string]int // term -> document ID -> term frequency
+	totalLen int
+}
+
+type bm25Doc struct {
+	doc    vectorstore.Document
+	length int
+}
+
+var _ Retriever = (*BM25)(nil)
+
+// NewBM25 creates an empty index with the default parameters.
+func NewBM25() *BM25 {
+	return &BM25{
+		K1:       1.2,
+		B:        0.75,
+		docs:     map[string]bm25Doc{},
+		postings: map[string]map[string]int{},
+	}
+}
+
+// Upsert indexes the documents, replacing any existing document with the same ID.
+func (b *BM25) Upsert(ctx context.Context, docs ...vectorstore.Document) error {
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return errors.New("document id is required")
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, doc := range docs {
+		b.remove(doc.ID)
+
+		terms := Tokenize(doc.Content)
+		for _, term := range terms {
+			if b.postings[term] == nil {
+				b.postings[term] = map[string]int{}
+			}
+			b.postings[term][doc.ID]++
+		}
+		b.docs[doc.ID] = bm25Doc{doc: doc, length: len(terms)}
+		b.totalLen += len(terms)
+	}
+	return nil
+}
+
+// Delete removes the documents with the given IDs.
+func (b *BM25) Delete(ctx context.Context, ids ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, id := range ids {
+		b.remove(id)
+	}
+	return nil
+}
+
+// Len returns the number of indexed documents.
+func (b *BM25) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.docs)
+}
+
+func (b *BM25) remove(id string) {
+	old, ok := b.docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range Tokenize(old.doc.Content) {
+		delete(b.postings[term], id)
+		if len(b.postings[term]) == 0 {
+			delete(b.postings, term)
+		}
+	}
+	b.totalLen -= old.length
+	delete(b.docs, id)
+}
+
+// Retrieve returns the documents with the highest BM25 score for query.
+// Documents sharing no term with the query are never returned.
+func (b *BM25) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	opts := vectorstore.NewSearchOptions(options...)
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if len(b.docs) == 0 {
+		return []vectorstore.Result{}, nil
+	}
+
+	n := float64(len(b.docs))
+	avgLen := float64(b.totalLen) / n
+
+	scores := map[string]float64{}
+	seen := map[string]bool{}
+	for _, term := range Tokenize(query) {
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+
+		postings := b.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range postings {
+			length := float64(b.docs[id].length)
+			f := float64(tf)
+			scores[id] += idf * f * (b.K1 + 1) / (f + b.K1*(1-b.B+b.B*length/avgLen))
+		}
+	}
+
+	results := []vectorstore.Result{}
+	for id, score := range scores {
+		doc := b.docs[id].doc
+		if opts.Filter != nil {
+			ok, err := opts.Filter.Match(doc.Metadata)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		if opts.ScoreThreshold != nil && float32(score) < *opts.ScoreThreshold {
+			continue
+		}
+		results = append(results, vectorstore.Result{Document: doc, Score: float32(score)})
+	}
+
+	sortResults(results)
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	return results, nil
+}
+
+// Tokenize lower-cases text and splits it into terms for keyword matching.
+// Letters, digits and underscores form terms, so identifiers such as ERR_TIMEOUT
+// or E1234 are kept intact.
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r) && r != '_'
+	})
+}
+
+func sortResults(results []vectorstore.Result) {
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+}
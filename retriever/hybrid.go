@@ -0,0 +1,102 @@
+package retriever
+
+import (
+	"context"
+
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// Hybrid combines several retrievers, typically BM25 and Vector, with reciprocal rank
+// fusion (RRF). Each document scores the sum of Weight / (K + rank) over the result
+// lists it appears in, so documents ranked highly by any retriever rise to the top
+// without having to calibrate the retrievers' incomparable raw scores.
+type Hybrid struct {
+	// K dampens the influence of top ranks, default 60.
+	K float64
+	// Candidates is the number of results requested from each retriever before fusion.
+	// When zero, four times the requested TopK is used.
+	Candidates int
+
+	retrievers []Retriever
+	weights    []float64
+}
+
+var _ Retriever = (*Hybrid)(nil)
+
+// NewHybrid creates a Hybrid retriever fusing the given retrievers with equal weight.
+func NewHybrid(retrievers ...Retriever) *Hybrid {
+	weights := make([]float64, len(retrievers))
+	for i := range weights {
+		weights[i] = 1
+	}
+	return &Hybrid{K: 60, retrievers: retrievers, weights: weights}
+}
+
+// WithWeights sets the weight of each retriever, in the order they were given to NewHybrid.
+// Missing weights keep their current value.
+func (h *Hybrid) WithWeights(weights ...float64) *Hybrid {
+	copy(h.weights, weights)
+	return h
+}
+
+// Retrieve queries every retriever and returns the fused ranking. Result scores are RRF
+// scores. The filter is applied by the underlying retrievers; the score threshold is
+// applied to the fused scores.
+func (h *Hybrid) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	opts := vectorstore.NewSearchOptions(options...)
+
+	candidates := h.Candidates
+	if candidates <= 0 {
+		candidates = opts.TopK * 4
+	}
+
+	childOptions := []vectorstore.SearchOption{vectorstore.WithTopK(candidates)}
+	if opts.Filter != nil {
+		childOptions = append(childOptions, vectorstore.WithFilter(opts.Filter))
+	}
+
+	lists := make([][]vectorstore.Result, len(h.retrievers))
+	for i, r := range h.retrievers {
+		results, err := r.Retrieve(ctx, query, childOptions...)
+		if err != nil {
+			return nil, err
+		}
+		lists[i] = results
+	}
+
+	return ReciprocalRankFusion(h.K, h.weights, lists, opts), nil
+}
+
+// ReciprocalRankFusion merges ranked result lists, identifying documents by ID.
+// weights[i] applies to lists[i]; the first occurrence of a document provides its content.
+func ReciprocalRankFusion(k float64, weights []float64, lists [][]vectorstore.Result, opts vectorstore.SearchOptions) []vectorstore.Result {
+	scores := map[string]float64{}
+	docs := map[string]vectorstore.Document{}
+
+	for i, list := range lists {
+		weight := 1.0
+		if i < len(weights) {
+			weight = weights[i]
+		}
+		for rank, result := range list {
+			scores[result.ID] += weight / (k + float64(rank+1))
+			if _, ok := docs[result.ID]; !ok {
+				docs[result.ID] = result.Document
+			}
+		}
+	}
+
+	results := make([]vectorstore.Result, 0, len(scores))
+	for id, score := range scores {
+		if opts.ScoreThreshold != nil && float32(score) < *opts.ScoreThreshold {
+			continue
+		}
+		results = append(results, vectorstore.Result{Document: docs[id], Score: float32(score)})
+	}
+
+	sortResults(results)
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	return results
+}
@@ -0,0 +1,60 @@
+package retriever
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobenpark/gothought/vectorstore"
+	"github.com/stretchr/testify/require"
+)
+
+type staticRetriever []string
+
+func (s staticRetriever) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	results := make([]vectorstore.Result, len(s))
+	for i, id := range s {
+		results[i] = vectorstore.Result{Document: vectorstore.Document{ID: id}}
+	}
+	return results, nil
+}
+
+func TestBM25_Retrieve(t *testing.T) {
+	ctx := context.TODO()
+	index := NewBM25()
+	require.NoError(t, index.Upsert(ctx,
+		vectorstore.Document{ID: "1", Content: "Connection reset by peer returns ERR_CONN_RESET", Metadata: map[string]any{"kind": "error"}},
+		vectorstore.Document{ID: "2", Content: "The connection pool keeps idle connections open"},
+		vectorstore.Document{ID: "3", Content: "Timeouts surface as ERR_TIMEOUT after 30 seconds", Metadata: map[string]any{"kind": "error"}},
+	))
+
+	results, err := index.Retrieve(ctx, "what does err_conn_reset mean")
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "1", results[0].ID)
+
+	results, err = index.Retrieve(ctx, "connection errors", vectorstore.WithFilter(vectorstore.Filter{"kind": "error"}))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, "1", results[0].ID)
+
+	require.NoError(t, index.Upsert(ctx, vectorstore.Document{ID: "1", Content: "replaced"}))
+	require.NoError(t, index.Delete(ctx, "3"))
+	require.Equal(t, 2, index.Len())
+
+	results, err = index.Retrieve(ctx, "ERR_CONN_RESET ERR_TIMEOUT")
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestHybrid_Retrieve(t *testing.T) {
+	hybrid := NewHybrid(staticRetriever{"a", "b", "c"}, staticRetriever{"b", "d", "c"})
+
+	results, err := hybrid.Retrieve(context.TODO(), "q", vectorstore.WithTopK(3))
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c", "a"}, []string{results[0].ID, results[1].ID, results[2].ID})
+	require.InDelta(t, 1.0/62+1.0/61, results[0].Score, 1e-6)
+
+	results, err = hybrid.WithWeights(1, 0).Retrieve(context.TODO(), "q", vectorstore.WithTopK(1))
+	require.NoError(t, err)
+	require.Equal(t, "a", results[0].ID)
+}
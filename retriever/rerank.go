@@ -0,0 +1,104 @@
+package retriever
+
+import (
+	"context"
+
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// Reranker reorders candidate results by their relevance to a query.
+// Implementations return the results best first and may drop irrelevant ones.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, results []vectorstore.Result) ([]vectorstore.Result, error)
+}
+
+// Expander rewrites a query into one or more queries that are searched instead.
+// Implementations usually include the original query in the returned list.
+type Expander interface {
+	Expand(ctx context.Context, query string) ([]string, error)
+}
+
+// Reranked retrieves a wide set of candidates from a retriever and reorders them
+// with a reranker before returning the requested TopK.
+type Reranked struct {
+	// Candidates is the number of results fetched before reranking.
+	// When zero, four times the requested TopK is used.
+	Candidates int
+
+	retriever Retriever
+	reranker  Reranker
+}
+
+var _ Retriever = (*Reranked)(nil)
+
+// NewReranked creates a new instance of Reranked
+func NewReranked(r Retriever, reranker Reranker) *Reranked {
+	return &Reranked{retriever: r, reranker: reranker}
+}
+
+// Retrieve fetches candidates, reranks them and returns the best TopK.
+func (r *Reranked) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	opts := vectorstore.NewSearchOptions(options...)
+
+	candidates := r.Candidates
+	if candidates <= 0 {
+		candidates = opts.TopK * 4
+	}
+
+	childOptions := append(append([]vectorstore.SearchOption{}, options...), vectorstore.WithTopK(candidates))
+	results, err := r.retriever.Retrieve(ctx, query, childOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err = r.reranker.Rerank(ctx, query, results)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	return results, nil
+}
+
+// MultiQuery expands a query into several queries, retrieves results for each of them
+// and fuses the result lists with reciprocal rank fusion.
+type MultiQuery struct {
+	// K is the reciprocal rank fusion constant, default 60.
+	K float64
+
+	retriever Retriever
+	expander  Expander
+}
+
+var _ Retriever = (*MultiQuery)(nil)
+
+// NewMultiQuery creates a new instance of MultiQuery
+func NewMultiQuery(r Retriever, expander Expander) *MultiQuery {
+	return &MultiQuery{K: 60, retriever: r, expander: expander}
+}
+
+// Retrieve searches every expanded query and returns the fused ranking.
+func (m *MultiQuery) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	queries, err := m.expander.Expand(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(queries) == 0 {
+		queries = []string{query}
+	}
+
+	lists := make([][]vectorstore.Result, len(queries))
+	for i, q := range queries {
+		results, err := m.retriever.Retrieve(ctx, q, options...)
+		if err != nil {
+			return nil, err
+		}
+		lists[i] = results
+	}
+
+	opts := vectorstore.NewSearchOptions(options...)
+	opts.ScoreThreshold = nil
+	return ReciprocalRankFusion(m.K, nil, lists, opts), nil
+}
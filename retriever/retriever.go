@@ -0,0 +1,39 @@
+package retriever
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// Retriever finds the documents most relevant to a text query.
+// Options have the same meaning as for vectorstore.VectorStore.Search; scores are
+// only comparable between results of the same retriever.
+type Retriever interface {
+	Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error)
+}
+
+// Vector retrieves documents by embedding the query and searching a vector store.
+type Vector struct {
+	embedder embedding.Embedder
+	store    vectorstore.VectorStore
+}
+
+// NewVector creates a new instance of Vector
+func NewVector(e embedding.Embedder, store vectorstore.VectorStore) *Vector {
+	return &Vector{embedder: e, store: store}
+}
+
+// Retrieve embeds query and returns the most similar documents in the store.
+func (v *Vector) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	vectors, err := v.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, errors.New("embedder returned unexpected number of vectors")
+	}
+	return v.store.Search(ctx, vectors[0], options...)
+}
@@ -0,0 +1,192 @@
+package gothought
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/tool"
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// SemanticCache is a Cache that matches conversations by meaning instead of exact text.
+// It embeds the final user message and returns a previously stored response when the
+// cosine similarity exceeds the configured threshold. Entries are scoped by the rest of
+// the conversation and the set of registered tools, so a cached answer is never reused
+// under different instructions, earlier turns or capabilities.
+type SemanticCache struct {
+	mu        sync.Mutex
+	embedder  embedding.Embedder
+	threshold float32
+	store     *vectorstore.Memory
+	responses map[string]Message
+	seq       int
+}
+
+// NewSemanticCache creates a SemanticCache using the given embedder.
+// threshold is the minimum cosine similarity (between -1 and 1) for a cache hit.
+func NewSemanticCache(e embedding.Embedder, threshold float32) *SemanticCache {
+	return &SemanticCache{
+		embedder:  e,
+		threshold: threshold,
+		store:     vectorstore.NewMemory(vectorstore.Cosine),
+		responses: map[string]Message{},
+	}
+}
+
+// Get embeds the final user message and returns the most similar cached response
+// within the same scope, if its similarity reaches the threshold.
+func (s *SemanticCache) Get(ctx context.Context, tools map[string]tool.Tool, messages []Message) (*Message, bool, error) {
+	query, ok := lastUserMessage(messages)
+	if !ok || s.Len() == 0 {
+		return nil, false, nil
+	}
+
+	vector, err := s.embed(ctx, query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	results, err := s.store.Search(ctx, vector,
+		vectorstore.WithTopK(1),
+		vectorstore.WithScoreThreshold(s.threshold),
+		vectorstore.WithFilter(vectorstore.Filter{"scope": cacheScope(tools, messages)}),
+	)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(results) == 0 {
+		return nil, false, nil
+	}
+
+	s.mu.Lock()
+	response, ok := s.responses[results[0].ID]
+	s.mu.Unlock()
+	if !ok {
+		return nil, false, nil
+	}
+	return &response, true, nil
+}
+
+// Set embeds the final user message and stores the response under the conversation's scope.
+func (s *SemanticCache) Set(ctx context.Context, tools map[string]tool.Tool, messages []Message, response *Message) error {
+	if response == nil {
+		return nil
+	}
+
+	query, ok := lastUserMessage(messages)
+	if !ok {
+		return nil
+	}
+
+	vector, err := s.embed(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	// hold the lock until the vector is stored, so a concurrent Clear cannot miss it
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	id := strconv.Itoa(s.seq)
+	if err := s.store.Upsert(ctx, vectorstore.Document{
+		ID:       id,
+		Content:  query,
+		Vector:   vector,
+		Metadata: map[string]any{"scope": cacheScope(tools, messages)},
+	}); err != nil {
+		return err
+	}
+	s.responses[id] = *response
+	return nil
+}
+
+// Len returns the number of cached responses across all scopes.
+func (s *SemanticCache) Len() int {
+	return s.store.Len()
+}
+
+// Clear removes every cached response.
+func (s *SemanticCache) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0, len(s.responses))
+	for id := range s.responses {
+		ids = append(ids, id)
+	}
+	_ = s.store.Delete(context.Background(), ids...)
+	s.responses = map[string]Message{}
+}
+
+func (s *SemanticCache) embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := s.embedder.Embed(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, errors.New("embedder returned unexpected number of vectors")
+	}
+	return vectors[0], nil
+}
+
+// lastUserMessage returns the content of the final message with the "user" role.
+func lastUserMessage(messages []Message) (string, bool) {
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			return messages[i].Message, true
+		}
+	}
+	return "", false
+}
+
+// cacheScope derives a key from every message except the final user message, which is
+// matched by similarity, and the registered tool names.
+func cacheScope(tools map[string]tool.Tool, messages []Message) string {
+	last := -1
+	for i := len(messages) - 1; i >= 0; i-- {
+		if messages[i].Role == "user" {
+			last = i
+			break
+		}
+	}
+
+	h := sha256.New()
+	for i, m := range messages {
+		if i == last {
+			continue
+		}
+		h.Write([]byte(m.Role))
+		h.Write([]byte{0})
+		h.Write([]byte(m.Message))
+		h.Write([]byte{0})
+		h.Write([]byte(m.ToolCallID))
+		h.Write([]byte{0})
+		for _, call := range m.ToolCalls {
+			h.Write([]byte(call.Function.Name))
+			h.Write([]byte{0})
+			h.Write([]byte(call.Function.Arguments))
+			h.Write([]byte{0})
+		}
+		h.Write([]byte{2})
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h.Write([]byte{1})
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,72 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEmbedder struct {
+	vectors map[string][]float32
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = f.vectors[text]
+	}
+	return out, nil
+}
+
+func (f *fakeEmbedder) Dimensions() int {
+	return 3
+}
+
+func (f *fakeEmbedder) Model() string {
+	return "fake"
+}
+
+func TestSemanticCache(t *testing.T) {
+	embedder := &fakeEmbedder{vectors: map[string][]float32{
+		"What is the capital of France?":  {1, 0, 0},
+		"Tell me France's capital city":   {0.98, 0.2, 0},
+		"How do I read a file in Go?":     {0, 1, 0},
+		"What is the capital of Germany?": {0.5, 0, 0.86},
+	}}
+	provider := &fakeProvider{responses: []Message{{Message: "Paris"}, {Message: "os.ReadFile"}}}
+	cache := NewSemanticCache(embedder, 0.95)
+
+	ask := func(system, question string) string {
+		model := NewLanguageModel(provider, WithCache(cache))
+		res, err := model.SystemPrompt(system).HumanPrompt(question).Q(context.TODO())
+		require.NoError(t, err)
+		return res.Message
+	}
+
+	require.Equal(t, "Paris", ask("geo", "What is the capital of France?"))
+	require.Equal(t, 1, provider.calls)
+
+	require.Equal(t, "Paris", ask("geo", "Tell me France's capital city"))
+	require.Equal(t, 1, provider.calls)
+
+	require.Equal(t, "os.ReadFile", ask("geo", "How do I read a file in Go?"))
+	require.Equal(t, 2, provider.calls)
+
+	ask("geo", "What is the capital of Germany?")
+	require.Equal(t, 3, provider.calls)
+
+	ask("other system prompt", "What is the capital of France?")
+	require.Equal(t, 4, provider.calls)
+	require.Equal(t, 4, cache.Len())
+
+	// a different earlier turn is a different conversation
+	model := NewLanguageModel(provider, WithCache(cache))
+	_, err := model.SystemPrompt("geo").HumanPrompt("Hi").AIPrompt("Hello!").HumanPrompt("What is the capital of France?").Q(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 5, provider.calls)
+	require.Equal(t, 5, cache.Len())
+
+	cache.Clear()
+	require.Zero(t, cache.Len())
+}
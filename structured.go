@@ -0,0 +1,264 @@
+package gothought
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gobenpark/gothought/jsonschema"
+)
+
+// Validator can be implemented by structured output types to check their own
+// invariants. QWith and QAs call Validate on the decoded value and treat an error
+// like a schema violation.
+type Validator interface {
+	Validate() error
+}
+
+// QAs queries the model and decodes its answer into a new value of type T.
+// The JSON schema sent to the model is derived from T, which may be a struct, a pointer
+// to a struct, or any other JSON-representable type such as a slice of structs.
+// Non-object types are wrapped in a {"result": ...} envelope for the model and unwrapped
+// before returning. If T (or *T) implements Validator, the decoded value is validated.
+// Invalid output is repaired as described for QWith.
+//
+//	type City struct {
+//		Name       string `json:"name" description:"city name"`
+//		Population int    `json:"population"`
+//	}
+//	cities, err := gothought.QAs[[]City](ctx, model.HumanPrompt("List three large cities in Korea."))
+func QAs[T any](ctx context.Context, model *LanguageModel, options ...CallOption) (T, error) {
+	var zero T
+	if len(model.messages) == 0 {
+		return zero, ErrEmptyConversation
+	}
+
+	target, value := newTarget[T]()
+	if err := model.QWith(ctx, target, options...); err != nil {
+		return zero, err
+	}
+	return value(), nil
+}
+
+// QStreamAs is the streaming variant of QAs. As the response streams in, the partial JSON
+// is closed and decoded into a progressively filled T, and onPartial is called with every
+// snapshot that differs from the previous one, starting from the zero value. The partial JSON
+// is only decoded when a chunk completes a key or a value, so strings are reported as they
+// stood at that point and the last string field may be incomplete. Numbers are held back until
+// they are complete, so a number is never reported with only some of its digits. When the
+// stream ends, the complete output is validated like in QWith and returned.
+//
+// Streaming does not run the tool loop, so no tools are offered to the model, and invalid
+// output is returned as an error instead of being repaired. The provider must implement
+// StreamingCapable; native structured output is used when it also implements
+// StructuredStreamingCapable and StructuredOutputCapable reports support.
+func QStreamAs[T any](ctx context.Context, model *LanguageModel, onPartial func(T) error) (T, error) {
+	var zero T
+	if len(model.messages) == 0 {
+		return zero, ErrEmptyConversation
+	}
+
+	target, value := newTarget[T]()
+	schema := jsonschema.Reflect(target)
+	messages := append([]Message(nil), model.messages...)
+
+	var (
+		text    strings.Builder
+		scanner jsonScanner
+		last    = value()
+	)
+	callback := func(chunk Message) error {
+		text.WriteString(chunk.Message)
+		if onPartial == nil || !scanner.scan(chunk.Message) {
+			return nil
+		}
+
+		received := text.String()
+		raw, err := ExtractJSON(received[:len(received)-partialNumber(received)])
+		if err != nil {
+			return nil
+		}
+
+		partial, value := newTarget[T]()
+		if err := json.Unmarshal([]byte(raw), partial); err != nil {
+			// the cut may fall inside a key or a literal; wait for more data
+			return nil
+		}
+		snapshot := value()
+		if reflect.DeepEqual(snapshot, last) {
+			return nil
+		}
+		last = snapshot
+		return onPartial(snapshot)
+	}
+
+	native, ok := model.provider.(StructuredStreamingCapable)
+	if capable, isCapable := model.provider.(StructuredOutputCapable); ok && isCapable && capable.SupportsStructuredOutput() {
+		format := ResponseFormat{Name: schemaName(target), Schema: schema}
+		if err := native.GenerateStructuredStreaming(ctx, messages, format, callback); err != nil {
+			return zero, err
+		}
+	} else if p, ok := model.provider.(StreamingCapable); ok {
+		messages = append(messages, Message{Role: "system", Message: GenerateSchemaPrompt(target)})
+		if err := p.GenerateStreaming(ctx, messages, callback); err != nil {
+			return zero, err
+		}
+	} else {
+		return zero, errors.New("streaming not supported for this provider")
+	}
+
+	final, value := newTarget[T]()
+	if err := decodeStructured(text.String(), schema, final); err != nil {
+		return zero, fmt.Errorf("invalid structured output: %w", err)
+	}
+	return value(), nil
+}
+
+// partialNumber returns the length of the number literal at the end of the streamed JSON
+// in s, which may still be missing digits, or 0 if s does not end inside a number.
+func partialNumber(s string) int {
+	start := strings.IndexAny(s, "{[")
+	if start < 0 {
+		return 0
+	}
+
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		switch c := s[i]; {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		}
+	}
+	if inString {
+		return 0
+	}
+
+	n := len(s) - len(strings.TrimRight(s, "0123456789.eE+-"))
+	if n == 0 || !strings.ContainsAny(s[len(s)-n:], "0123456789") {
+		return 0
+	}
+	return n
+}
+
+// jsonScanner follows a streamed JSON document chunk by chunk, keeping track of whether
+// it is inside a string, so QStreamAs decodes the accumulated output only when it changed.
+type jsonScanner struct {
+	started  bool
+	inString bool
+	escaped  bool
+}
+
+// scan reports whether chunk completes a key or a value: whether it contains a bracket,
+// brace, comma or colon outside of a string, or the end of a string. Text before the
+// first bracket or brace is skipped.
+func (s *jsonScanner) scan(chunk string) bool {
+	complete := false
+	for i := 0; i < len(chunk); i++ {
+		switch c := chunk[i]; {
+		case !s.started:
+			if c == '{' || c == '[' {
+				s.started, complete = true, true
+			}
+		case s.escaped:
+			s.escaped = false
+		case s.inString:
+			if c == '\\' {
+				s.escaped = true
+			} else if c == '"' {
+				s.inString, complete = false, true
+			}
+		case c == '"':
+			s.inString = true
+		case strings.IndexByte("{}[],:", c) >= 0:
+			complete = true
+		}
+	}
+	return complete
+}
+
+// newTarget allocates the value QWith decodes into for an output of type T, and returns
+// a function reading the decoded T back. Structs and pointers to structs are decoded
+// directly; any other type is wrapped in a {"result": ...} envelope.
+func newTarget[T any]() (any, func() T) {
+	t := reflect.TypeFor[T]()
+
+	switch {
+	case t.Kind() == reflect.Struct:
+		value := new(T)
+		return value, func() T { return *value }
+	case t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Struct:
+		ptr := reflect.New(t.Elem())
+		return ptr.Interface(), func() T { return ptr.Interface().(T) }
+	}
+
+	envelope := new(resultEnvelope[T])
+	return envelope, func() T { return envelope.Result }
+}
+
+// resultEnvelope wraps outputs that are not objects, such as slices.
+type resultEnvelope[T any] struct {
+	Result T `json:"result" description:"the requested output"`
+}
+
+// Validate validates the wrapped result, so QWith checks it inside its repair loop.
+func (e *resultEnvelope[T]) Validate() error {
+	return validate(&e.Result)
+}
+
+func (e *resultEnvelope[T]) isEnvelope() {}
+
+// schemaName derives a response format name from the type of v, such as "City" for *City.
+func schemaName(v any) string {
+	if _, ok := v.(interface{ isEnvelope() }); ok {
+		return "response"
+	}
+
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "response"
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, t.Name())
+
+	if name == "" {
+		return "response"
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+// validate calls Validate on v or on the value it points to, whichever implements Validator.
+func validate(v any) error {
+	if validator, ok := v.(Validator); ok {
+		return validator.Validate()
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+		if validator, ok := rv.Interface().(Validator); ok {
+			if rv.Kind() == reflect.Ptr && rv.IsNil() {
+				return nil
+			}
+			return validator.Validate()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+package gothought
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testCity struct {
+	Name       string `json:"name" description:"city name"`
+	Population int    `json:"population"`
+}
+
+func (c testCity) Validate() error {
+	if c.Population < 0 {
+		return errors.New("population must not be negative")
+	}
+	return nil
+}
+
+func TestQAs(t *testing.T) {
+	ctx := context.TODO()
+
+	provider := &fakeProvider{responses: []Message{{Message: "```json\n{\"name\": \"Seoul\", \"population\": 9400000}\n```"}}}
+	city, err := QAs[testCity](ctx, NewLanguageModel(provider).HumanPrompt("Largest city in Korea?"))
+	require.NoError(t, err)
+	require.Equal(t, testCity{Name: "Seoul", Population: 9400000}, city)
+
+	ptr, err := QAs[*testCity](ctx, NewLanguageModel(provider).HumanPrompt("Largest city in Korea?"))
+	require.NoError(t, err)
+	require.Equal(t, "Seoul", ptr.Name)
+
+	provider = &fakeProvider{responses: []Message{{Message: "```json\n{\"result\": [{\"name\": \"Seoul\", \"population\": 9400000}, {\"name\": \"Busan\", \"population\": 3300000}]}\n```"}}}
+	cities, err := QAs[[]testCity](ctx, NewLanguageModel(provider).HumanPrompt("Two cities in Korea?"))
+	require.NoError(t, err)
+	require.Len(t, cities, 2)
+	require.Equal(t, "Busan", cities[1].Name)
+	require.Contains(t, provider.messages[len(provider.messages)-1].Message, `"result"`)
+
+	provider = &fakeProvider{responses: []Message{{Message: "```json\n{\"name\": \"Nowhere\", \"population\": -1}\n```"}}}
+	_, err = QAs[testCity](ctx, NewLanguageModel(provider).HumanPrompt("?"))
+	require.ErrorContains(t, err, "population must not be negative")
+
+	_, err = QAs[testCity](ctx, NewLanguageModel(provider))
+	require.Error(t, err)
+}
+
+type capitals []string
+
+func (c capitals) Validate() error {
+	for _, name := range c {
+		if name == "Busan" {
+			return errors.New("Busan is not a capital")
+		}
+	}
+	return nil
+}
+
+func TestQAs_SliceValidator(t *testing.T) {
+	provider := &fakeProvider{responses: []Message{
+		{Message: `{"result": ["Seoul", "Busan"]}`},
+		{Message: `{"result": ["Seoul", "Tokyo"]}`},
+	}}
+
+	result, err := QAs[capitals](context.TODO(), NewLanguageModel(provider).HumanPrompt("Capitals of Korea and Japan?"))
+	require.NoError(t, err)
+	require.Equal(t, capitals{"Seoul", "Tokyo"}, result)
+	require.Equal(t, 2, provider.calls)
+	require.Contains(t, provider.messages[len(provider.messages)-2].Message, "Busan is not a capital")
+}
+
+func TestExtractJSON(t *testing.T) {
+	cases := map[string]string{
+		"```json\n{\"a\": 1}\n```":                    `{"a": 1}`,
+		"Sure! ```\n[1, 2]\n``` done":                 `[1, 2]`,
+		"The answer is {\"a\": {\"b\": \"}\"}} ok":    `{"a": {"b": "}"}}`,
+		"{\"a\": [1, 2,], \"b\": 3,}":                 `{"a": [1, 2], "b": 3}`,
+		"```json\n{\"a\": [\"x\", \"y":                `{"a": ["x", "y"]}`,
+		"{\"a\": 1, \"b\":":                           `{"a": 1, "b":null}`,
+		"{\"a\": 1, \"bc":                             `{"a": 1}`,
+		"As shown in [1], the result is {\"a\": [2]}": `{"a": [2]}`,
+		"See [1] and [2]:\n[{\"a\": 1}]":              `[{"a": 1}]`,
+	}
+	for in, want := range cases {
+		got, err := ExtractJSON(in)
+		require.NoError(t, err, in)
+		require.JSONEq(t, want, got, in)
+	}
+
+	_, err := ExtractJSON("no json here")
+	require.Error(t, err)
+}
+
+func TestQWith_ProseBeforeJSON(t *testing.T) {
+	// the longest value does not match the schema, the object after it does
+	provider := &fakeProvider{responses: []Message{
+		{Message: `According to [1] and the sources ["2020 census", "2021 estimate", "2022 estimate", "2023 estimate"]: {"name": "Seoul", "population": 9400000}`},
+	}}
+
+	var city testCity
+	require.NoError(t, NewLanguageModel(provider).HumanPrompt("Largest city in Korea?").QWith(context.TODO(), &city))
+	require.Equal(t, testCity{Name: "Seoul", Population: 9400000}, city)
+	require.Equal(t, 1, provider.calls)
+}
+
+func TestQWith_Repair(t *testing.T) {
+	provider := &fakeProvider{responses: []Message{
+		{Message: "{\"name\": \"Seoul\", \"population\": \"many\"}"},
+		{Message: "{\"name\": \"Seoul\", \"population\": 9400000}"},
+	}}
+	model := NewLanguageModel(provider).HumanPrompt("Largest city in Korea?")
+
+	var city testCity
+	require.NoError(t, model.QWith(context.TODO(), &city))
+	require.Equal(t, 9400000, city.Population)
+	require.Equal(t, 2, provider.calls)
+
+	repair := provider.messages[len(provider.messages)-2]
+	require.Equal(t, "user", repair.Role)
+	require.Contains(t, repair.Message, "/population: expected integer, got string")
+	require.Equal(t, []Message{{Role: "user", Message: "Largest city in Korea?"}}, model.messages)
+
+	provider = &fakeProvider{responses: []Message{{Message: "not json"}}}
+	err := NewLanguageModel(provider, WithRepairAttempts(0)).HumanPrompt("?").QWith(context.TODO(), &city)
+	require.Error(t, err)
+	require.Equal(t, 1, provider.calls)
+}
+
+func TestQWith_History(t *testing.T) {
+	ctx := context.TODO()
+	call := ToolCalls{ID: "call_1", Type: "function"}
+	call.Function.Name = "lookup"
+	call.Function.Arguments = `{"city": "Seoul"}`
+
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{call}},
+		{Message: "{\"name\": \"Seoul\", \"population\": 9400000}"},
+	}}
+	lookup := &fakeTool{name: "lookup", result: "Seoul has 9.4 million inhabitants"}
+	model := NewLanguageModel(provider).AddTool(lookup).HumanPrompt("How many people live in Seoul?")
+
+	for i := 0; i < 2; i++ {
+		var city testCity
+		require.NoError(t, model.QWith(ctx, &city))
+		require.Equal(t, 9400000, city.Population)
+	}
+	require.Equal(t, 2, lookup.calls)
+	require.Equal(t, []Message{{Role: "user", Message: "How many people live in Seoul?"}}, model.messages)
+
+	// tool results are part of the request, followed by the transient schema instruction
+	require.Len(t, provider.messages, 4)
+	require.Equal(t, "tool", provider.messages[2].Role)
+	require.Equal(t, "system", provider.messages[3].Role)
+
+	var city testCity
+	require.ErrorIs(t, NewLanguageModel(provider).QWith(ctx, &city), ErrEmptyConversation)
+}
+
+type fakeStreamingProvider struct {
+	fakeProvider
+	chunks []string
+}
+
+func (f *fakeStreamingProvider) GenerateStreaming(ctx context.Context, messages []Message, callback func(Message) error) error {
+	f.messages = messages
+	for _, chunk := range f.chunks {
+		if err := callback(Message{Message: chunk}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestQStreamAs(t *testing.T) {
+	provider := &fakeStreamingProvider{chunks: []string{
+		"```json\n{\"na", "me\": \"Se", "oul\", \"popul", "ation\": 94", "00000}", "\n```",
+	}}
+	model := NewLanguageModel(provider).HumanPrompt("Largest city in Korea?")
+
+	var snapshots []testCity
+	city, err := QStreamAs(context.TODO(), model, func(partial testCity) error {
+		snapshots = append(snapshots, partial)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, testCity{Name: "Seoul", Population: 9400000}, city)
+	require.Equal(t, []testCity{
+		{Name: "Se"},
+		{Name: "Seoul"},
+		{Name: "Seoul", Population: 9400000},
+	}, snapshots)
+	require.Equal(t, "system", provider.messages[len(provider.messages)-1].Role)
+
+	provider.chunks = []string{`{"result": [{"name": "Seoul", "population": 1}`, `, {"name": "Bu`, `san", "population": 2}]}`}
+	var sizes []int
+	cities, err := QStreamAs(context.TODO(), model, func(partial []testCity) error {
+		sizes = append(sizes, len(partial))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, cities, 2)
+	require.Equal(t, []int{1, 2, 2}, sizes)
+
+	// a number followed only by the closing fence is complete
+	provider.chunks = []string{"```json\n{\"result\": [1, 2", "3]}\n```"}
+	var numbers [][]int
+	result, err := QStreamAs(context.TODO(), model, func(partial []int) error {
+		numbers = append(numbers, partial)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 23}, result)
+	require.Equal(t, [][]int{{1}, {1, 23}}, numbers)
+}
+
+func TestPartialNumber(t *testing.T) {
+	for in, want := range map[string]int{
+		`{"a": 94`:            2,
+		`{"a": -1.5e`:         5,
+		`{"a": 94,`:           0,
+		`{"a": 94 `:           0,
+		`{"a": "94`:           0,
+		`{"a": "x\"", "b": 1`: 1,
+		`{"a": [1, 2`:         1,
+		`{"a": true`:          0,
+		`no json 42`:          0,
+	} {
+		require.Equal(t, want, partialNumber(in), in)
+	}
+}
+
+func TestJSONScanner(t *testing.T) {
+	var scanner jsonScanner
+	var complete []bool
+	for _, chunk := range []string{"Sure: ", "```json\n{", `"na`, `me"`, `: "a, [b]`, ` \"c\"`, `"`, ` `, `}`} {
+		complete = append(complete, scanner.scan(chunk))
+	}
+	require.Equal(t, []bool{false, true, false, true, true, false, true, false, true}, complete)
+}
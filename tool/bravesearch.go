@@ -45,12 +45,14 @@ type BraveSearchParams struct {
 // BraveSearchTool implements the Tool interface for searching with Brave
 type BraveSearchTool struct {
 	apiKey string
+	api    httpConfig
 }
 
 // NewBraveSearchTool creates a new instance of BraveSearchTool
-func NewBraveSearchTool(key string) *BraveSearchTool {
+func NewBraveSearchTool(key string, options ...HTTPOption) *BraveSearchTool {
 	return &BraveSearchTool{
 		apiKey: key,
+		api:    newHTTPConfig("https://api.search.brave.com/res/v1/web/search", "", options),
 	}
 }
 
@@ -110,8 +112,7 @@ func (b *BraveSearchTool) Call(ctx context.Context, params string) (string, erro
 	}
 
 	// Build the request URL
-	baseURL := "https://api.search.brave.com/res/v1/web/search"
-	u, err := url.Parse(baseURL)
+	u, err := url.Parse(b.api.baseURL)
 	if err != nil {
 		return "", err
 	}
@@ -136,11 +137,11 @@ func (b *BraveSearchTool) Call(ctx context.Context, params string) (string, erro
 	req.Header.Add("X-Subscription-Token", b.apiKey)
 
 	// Execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, cancel, err := b.api.do(req)
 	if err != nil {
 		return "", err
 	}
+	defer cancel()
 	defer resp.Body.Close()
 
 	// Check for errors
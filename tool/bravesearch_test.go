@@ -0,0 +1,47 @@
+package tool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBraveSearchTool_Call(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Subscription-Token"))
+		assert.Equal(t, "gothought/1.0", r.Header.Get("User-Agent"))
+		assert.Equal(t, "golang", r.URL.Query().Get("q"))
+		assert.Equal(t, "5", r.URL.Query().Get("count"))
+		assert.Equal(t, "10", r.URL.Query().Get("offset"))
+		w.Write([]byte(`{"web": {"results": [
+			{"title": "The Go Programming Language", "url": "https://go.dev", "description": "Build simple, secure, scalable systems with Go."}
+		]}}`))
+	}))
+	defer server.Close()
+
+	tool := NewBraveSearchTool("secret", WithAPIBaseURL(server.URL), WithAPIHTTPClient(server.Client()), WithAPIUserAgent("gothought/1.0"))
+	result, err := tool.Call(context.TODO(), `{"query": "golang", "count": 5, "offset": 10}`)
+	require.NoError(t, err)
+	require.Equal(t, `Search results for 'golang':
+
+1. The Go Programming Language
+   URL: https://go.dev
+   Build simple, secure, scalable systems with Go.
+
+`, result)
+}
+
+func TestBraveSearchTool_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	tool := NewBraveSearchTool("wrong", WithAPIBaseURL(server.URL))
+	_, err := tool.Call(context.TODO(), `{"query": "golang"}`)
+	require.EqualError(t, err, "API returned error: invalid token\n (status code: 401)")
+}
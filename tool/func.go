@@ -0,0 +1,105 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/gobenpark/gothought/jsonschema"
+)
+
+// Func implements the Tool interface for a typed Go function.
+// The parameter schema is derived from the struct tags of In (see jsonschema.ReflectType),
+// arguments are validated against it and decoded into In, and the returned Out is
+// serialised as JSON, or passed through unchanged when it is a string.
+type Func[In, Out any] struct {
+	name        string
+	description string
+	fn          func(ctx context.Context, in In) (Out, error)
+	schema      *jsonschema.Schema
+}
+
+// NewFunc creates a tool calling fn.
+//
+//	type WeatherParams struct {
+//		City string `json:"city" description:"city name"`
+//		Unit string `json:"unit,omitempty" enum:"celsius,fahrenheit" default:"celsius"`
+//	}
+//	weather := tool.NewFunc("get_weather", "Returns the current weather for a city",
+//		func(ctx context.Context, p WeatherParams) (Weather, error) { ... })
+func NewFunc[In, Out any](name string, description string, fn func(ctx context.Context, in In) (Out, error)) *Func[In, Out] {
+	schema := jsonschema.For[In]()
+	schema.Schema = ""
+	return &Func[In, Out]{name: name, description: description, fn: fn, schema: schema}
+}
+
+// Name returns the name of the tool
+func (f *Func[In, Out]) Name() string {
+	return f.name
+}
+
+// Description returns a description of the tool
+func (f *Func[In, Out]) Description() string {
+	return f.description
+}
+
+// ParameterSchema returns the schema derived from In
+func (f *Func[In, Out]) ParameterSchema() map[string]interface{} {
+	return f.schema.ToMap()
+}
+
+// Call validates and decodes params into In, calls the function and encodes its result
+func (f *Func[In, Out]) Call(ctx context.Context, params string) (string, error) {
+	res, err := f.CallResult(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return res.Content(), nil
+}
+
+// CallResult is like Call but returns a Result. When Out is *Result, it is returned as is,
+// which lets typed functions report errors to the model or attach artifacts and metadata.
+func (f *Func[In, Out]) CallResult(ctx context.Context, params string) (*Result, error) {
+	if params == "" {
+		params = "{}"
+	}
+
+	if err := f.schema.ValidateJSON([]byte(params)); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+
+	var in In
+	if err := json.Unmarshal([]byte(params), &in); err != nil {
+		return nil, fmt.Errorf("invalid parameters: %v", err)
+	}
+	if v, ok := any(&in).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid parameters: %w", err)
+		}
+	}
+
+	out, err := f.fn(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := any(out).(type) {
+	case *Result:
+		if v == nil {
+			return &Result{}, nil
+		}
+		return v, nil
+	case string:
+		return TextResult(v), nil
+	}
+	if rv := reflect.ValueOf(out); !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return TextResult("null"), nil
+	}
+
+	bt, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return TextResult(string(bt)), nil
+}
@@ -0,0 +1,57 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type weatherParams struct {
+	City string `json:"city" description:"city name" minLength:"1"`
+	Unit string `json:"unit,omitempty" enum:"celsius,fahrenheit"`
+}
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+}
+
+func TestFunc_Call(t *testing.T) {
+	var got weatherParams
+	tool := NewFunc("get_weather", "Returns the weather", func(ctx context.Context, p weatherParams) (weather, error) {
+		got = p
+		if p.City == "Atlantis" {
+			return weather{}, errors.New("unknown city")
+		}
+		return weather{City: p.City, Temperature: 21.5}, nil
+	})
+
+	require.Equal(t, "get_weather", tool.Name())
+	schema := tool.ParameterSchema()
+	require.Equal(t, "object", schema["type"])
+	require.Equal(t, []interface{}{"city"}, schema["required"])
+	require.NotContains(t, schema, "$schema")
+
+	result, err := tool.Call(context.TODO(), `{"city": "Seoul", "unit": "celsius"}`)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"city": "Seoul", "temperature": 21.5}`, result)
+	require.Equal(t, weatherParams{City: "Seoul", Unit: "celsius"}, got)
+
+	_, err = tool.Call(context.TODO(), `{"unit": "kelvin"}`)
+	require.ErrorContains(t, err, `missing required property "city"`)
+	require.ErrorContains(t, err, `/unit: must be one of`)
+
+	_, err = tool.Call(context.TODO(), `{"city": "Atlantis"}`)
+	require.EqualError(t, err, "unknown city")
+
+	echo := NewFunc("echo", "Echoes text", func(ctx context.Context, p struct {
+		Text string `json:"text"`
+	}) (string, error) {
+		return p.Text, nil
+	})
+	result, err = echo.Call(context.TODO(), `{"text": "hi"}`)
+	require.NoError(t, err)
+	require.Equal(t, "hi", result)
+}
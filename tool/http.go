@@ -0,0 +1,74 @@
+package tool
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// HTTPOption configures how BraveSearchTool and WikipediaTool reach their APIs.
+type HTTPOption func(c *httpConfig)
+
+type httpConfig struct {
+	baseURL   string
+	client    *http.Client
+	userAgent string
+	timeout   time.Duration
+}
+
+// WithAPIBaseURL url of the API endpoint, e.g. a proxy or a test server
+func WithAPIBaseURL(url string) HTTPOption {
+	return func(c *httpConfig) {
+		c.baseURL = url
+	}
+}
+
+// WithAPIHTTPClient client used for all requests, default http.DefaultClient
+func WithAPIHTTPClient(client *http.Client) HTTPOption {
+	return func(c *httpConfig) {
+		c.client = client
+	}
+}
+
+// WithAPIUserAgent value of the User-Agent header sent with every request
+func WithAPIUserAgent(userAgent string) HTTPOption {
+	return func(c *httpConfig) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithAPITimeout maximum duration of a single API request, 0 means no timeout beyond the caller's context
+func WithAPITimeout(timeout time.Duration) HTTPOption {
+	return func(c *httpConfig) {
+		c.timeout = timeout
+	}
+}
+
+func newHTTPConfig(baseURL, userAgent string, options []HTTPOption) httpConfig {
+	c := httpConfig{baseURL: baseURL, client: http.DefaultClient, userAgent: userAgent}
+	for _, option := range options {
+		option(&c)
+	}
+	return c
+}
+
+// do sends req with the configured user agent and client, bounded by the configured timeout.
+// The returned cancel function must be called once the response body has been read.
+func (c *httpConfig) do(req *http.Request) (*http.Response, context.CancelFunc, error) {
+	cancel := context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), c.timeout)
+		req = req.WithContext(ctx)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return resp, cancel, nil
+}
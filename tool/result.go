@@ -0,0 +1,130 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Result is the outcome of a tool call with more structure than a plain string.
+// Text, JSON and a summary of the artifacts are sent to the model (see Content);
+// Metadata is only available to the caller, for example the raw hits of a search.
+type Result struct {
+	// Text is the textual output shown to the model.
+	Text string
+	// JSON is an optional structured payload, encoded as JSON for the model.
+	JSON any
+	// Artifacts are images or other binary outputs produced by the tool.
+	Artifacts []Artifact
+	// IsError reports that the call failed in a way the model should see and may recover from,
+	// as opposed to the error returned by CallResult, which aborts the query.
+	IsError bool
+	// Metadata carries data for the caller that is never sent to the model.
+	Metadata map[string]any
+}
+
+// Artifact is a binary output of a tool, such as a generated image or file.
+type Artifact struct {
+	Name     string
+	MIMEType string
+	Data     []byte
+	// URI optionally points to where the artifact can be retrieved instead of Data.
+	URI string
+}
+
+// ResultTool is implemented by tools returning a rich Result.
+// Callers aware of it use CallResult instead of Call; the plain Call stays available for
+// everything else, usually returning the Content of the result.
+type ResultTool interface {
+	Tool
+
+	// CallResult executes the tool like Call and returns its structured result.
+	CallResult(ctx context.Context, params string) (*Result, error)
+}
+
+// TextResult returns a Result holding text.
+func TextResult(text string) *Result {
+	return &Result{Text: text}
+}
+
+// JSONResult returns a Result holding a structured payload.
+func JSONResult(v any) *Result {
+	return &Result{JSON: v}
+}
+
+// ErrorResult returns a Result reporting a failure to the model.
+func ErrorResult(format string, args ...any) *Result {
+	return &Result{Text: fmt.Sprintf(format, args...), IsError: true}
+}
+
+// Call executes t and returns its Result, wrapping the output of tools
+// that only implement the plain Tool interface as text.
+func Call(ctx context.Context, t Tool, params string) (*Result, error) {
+	if rt, ok := t.(ResultTool); ok {
+		res, err := rt.CallResult(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			res = &Result{}
+		}
+		return res, nil
+	}
+
+	text, err := t.Call(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return TextResult(text), nil
+}
+
+// Content renders the part of the result that is shown to the model: the text, the
+// JSON payload and one line per artifact. Error results are prefixed with "Error: ".
+func (r *Result) Content() string {
+	var parts []string
+	if r.Text != "" {
+		parts = append(parts, r.Text)
+	}
+
+	if r.JSON != nil {
+		bt, err := json.Marshal(r.JSON)
+		if err != nil {
+			parts = append(parts, fmt.Sprintf("(unencodable JSON payload: %v)", err))
+		} else {
+			parts = append(parts, string(bt))
+		}
+	}
+
+	for _, a := range r.Artifacts {
+		parts = append(parts, a.describe())
+	}
+
+	content := strings.Join(parts, "\n\n")
+	if r.IsError && !strings.HasPrefix(content, "Error:") {
+		content = "Error: " + content
+	}
+	return content
+}
+
+func (a Artifact) describe() string {
+	desc := "[artifact"
+	if a.Name != "" {
+		desc += " " + a.Name
+	}
+
+	var details []string
+	if a.MIMEType != "" {
+		details = append(details, a.MIMEType)
+	}
+	if len(a.Data) > 0 {
+		details = append(details, fmt.Sprintf("%d bytes", len(a.Data)))
+	}
+	if a.URI != "" {
+		details = append(details, a.URI)
+	}
+	if len(details) > 0 {
+		desc += " (" + strings.Join(details, ", ") + ")"
+	}
+	return desc + "]"
+}
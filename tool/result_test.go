@@ -0,0 +1,65 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResult_Content(t *testing.T) {
+	res := &Result{
+		Text: "Rendered the chart.",
+		JSON: map[string]int{"points": 3},
+		Artifacts: []Artifact{
+			{Name: "chart.png", MIMEType: "image/png", Data: []byte{1, 2, 3}},
+		},
+		Metadata: map[string]any{"secret": "not for the model"},
+	}
+	require.Equal(t, "Rendered the chart.\n\n{\"points\":3}\n\n[artifact chart.png (image/png, 3 bytes)]", res.Content())
+
+	require.Equal(t, "Error: city not found", ErrorResult("city %s", "not found").Content())
+}
+
+func TestCall(t *testing.T) {
+	ctx := context.TODO()
+
+	plain := NewFunc("echo", "Echoes", func(ctx context.Context, p weatherParams) (string, error) {
+		return p.City, nil
+	})
+	res, err := Call(ctx, plain, `{"city": "Seoul"}`)
+	require.NoError(t, err)
+	require.Equal(t, "Seoul", res.Text)
+
+	rich := NewFunc("lookup", "Looks up", func(ctx context.Context, p weatherParams) (*Result, error) {
+		if p.City == "Atlantis" {
+			return ErrorResult("unknown city %q", p.City), nil
+		}
+		return &Result{Text: "found", Metadata: map[string]any{"id": 7}}, nil
+	})
+	res, err = Call(ctx, rich, `{"city": "Seoul"}`)
+	require.NoError(t, err)
+	require.Equal(t, "found", res.Text)
+	require.Equal(t, 7, res.Metadata["id"])
+
+	res, err = Call(ctx, rich, `{"city": "Atlantis"}`)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+
+	text, err := rich.Call(ctx, `{"city": "Atlantis"}`)
+	require.NoError(t, err)
+	require.Equal(t, `Error: unknown city "Atlantis"`, text)
+}
+
+func TestIsTransient(t *testing.T) {
+	require.True(t, IsTransient(Transient(errors.New("busy"))))
+	require.True(t, IsTransient(fmt.Errorf("fetch: %w", context.DeadlineExceeded)))
+	require.True(t, IsTransient(&net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}))
+	require.False(t, IsTransient(context.Canceled))
+	require.False(t, IsTransient(errors.New("bad request")))
+	require.Nil(t, Transient(nil))
+}
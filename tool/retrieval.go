@@ -0,0 +1,159 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/retriever"
+	"github.com/gobenpark/gothought/vectorstore"
+)
+
+// RetrievalParams defines the parameters for a knowledge base search
+type RetrievalParams struct {
+	Query  string             `json:"query"`
+	Count  int                `json:"count"`
+	Filter vectorstore.Filter `json:"filter"`
+}
+
+// maxRetrievalCount is the largest number of chunks a single search returns.
+const maxRetrievalCount = 20
+
+// RetrievalTool implements the Tool interface for searching a knowledge base
+type RetrievalTool struct {
+	retriever retriever.Retriever
+	topK      int
+}
+
+// NewRetrievalTool creates a new instance of RetrievalTool.
+// The query is embedded with e and looked up in store, returning up to topK chunks by default.
+func NewRetrievalTool(e embedding.Embedder, store vectorstore.VectorStore, topK int) *RetrievalTool {
+	return NewRetrieverTool(retriever.NewVector(e, store), topK)
+}
+
+// NewRetrieverTool creates a RetrievalTool backed by any retriever, such as a
+// retriever.Hybrid combining keyword and vector search. topK is capped at 20.
+func NewRetrieverTool(r retriever.Retriever, topK int) *RetrievalTool {
+	if topK <= 0 {
+		topK = 4
+	}
+	return &RetrievalTool{retriever: r, topK: min(topK, maxRetrievalCount)}
+}
+
+// ParameterSchema function the parameters structure for a knowledge base search
+func (r *RetrievalTool) ParameterSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{
+				"type":        "string",
+				"description": "A natural language description of the information to look up. Query can not be empty.",
+			},
+			"count": map[string]interface{}{
+				"type":        "integer",
+				"description": fmt.Sprintf("Number of chunks to return (default: %d, max: %d)", r.topK, maxRetrievalCount),
+				"default":     r.topK,
+			},
+			"filter": map[string]interface{}{
+				"type": "object",
+				"description": `Optional metadata filter. Each key must match the document metadata, either by value ` +
+					`({"source": "guide.md"}) or with operators $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin ` +
+					`({"year": {"$gte": 2023}}).`,
+			},
+		},
+		"required": []string{"query"},
+	}
+}
+
+// Description returns a description of the tool
+func (r *RetrievalTool) Description() string {
+	return `Searches the private knowledge base for passages relevant to a query.
+Use this for questions about internal documents, product documentation, or any content that was indexed for this assistant.
+Results are numbered chunks with their sources; cite them by number when answering.`
+}
+
+// Name returns the name of the tool
+func (r *RetrievalTool) Name() string {
+	return "search_knowledge_base"
+}
+
+// Call executes a search against the knowledge base
+func (r *RetrievalTool) Call(ctx context.Context, params string) (string, error) {
+	res, err := r.CallResult(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return res.Content(), nil
+}
+
+// CallResult executes a search against the knowledge base. Besides the formatted chunks
+// sent to the model, the raw []vectorstore.Result is available in the "results" metadata.
+func (r *RetrievalTool) CallResult(ctx context.Context, params string) (*Result, error) {
+	var searchParams RetrievalParams
+	if err := json.Unmarshal([]byte(params), &searchParams); err != nil {
+		return nil, fmt.Errorf("invalid search parameters: %v", err)
+	}
+
+	if searchParams.Query == "" {
+		return ErrorResult("query parameter is required"), nil
+	}
+
+	if searchParams.Count <= 0 {
+		searchParams.Count = r.topK
+	}
+	searchParams.Count = min(searchParams.Count, maxRetrievalCount)
+
+	options := []vectorstore.SearchOption{vectorstore.WithTopK(searchParams.Count)}
+	if len(searchParams.Filter) > 0 {
+		options = append(options, vectorstore.WithFilter(searchParams.Filter))
+	}
+
+	results, err := r.retriever.Retrieve(ctx, searchParams.Query, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Text:     FormatResults(searchParams.Query, results),
+		Metadata: map[string]any{"results": results},
+	}, nil
+}
+
+// FormatResults renders search results as numbered chunks with their source citations.
+func FormatResults(query string, results []vectorstore.Result) string {
+	if len(results) == 0 {
+		return "No results found."
+	}
+
+	return fmt.Sprintf("Knowledge base results for '%s':\n\n", query) + FormatChunks(results)
+}
+
+// FormatChunks renders results as chunks numbered from 1, each with its Source, so that
+// answers can cite them as [n].
+func FormatChunks(results []vectorstore.Result) string {
+	var sb strings.Builder
+	for i, result := range results {
+		sb.WriteString(fmt.Sprintf("[%d] Source: %s\n", i+1, Source(result.Document)))
+		sb.WriteString(strings.TrimSpace(result.Content))
+		sb.WriteString("\n\n")
+	}
+	return sb.String()
+}
+
+// Source returns a human-readable citation for a document, built from its
+// "source", "title" and "url" metadata and falling back to the document ID.
+func Source(doc vectorstore.Document) string {
+	var parts []string
+	for _, key := range []string{"title", "source", "url"} {
+		if v, ok := doc.Metadata[key]; ok && fmt.Sprint(v) != "" {
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+
+	if len(parts) == 0 {
+		return doc.ID
+	}
+	return strings.Join(parts, " - ")
+}
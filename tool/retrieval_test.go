@@ -0,0 +1,71 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/vectorstore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetrievalTool_Call(t *testing.T) {
+	ctx := context.TODO()
+	embedder := embedding.NewHashEmbedder(128)
+	store := vectorstore.NewMemory(vectorstore.Cosine)
+
+	texts := []string{
+		"Goroutines are lightweight threads managed by the Go runtime.",
+		"Channels let goroutines communicate by passing values.",
+		"The borrow checker enforces memory safety in Rust.",
+	}
+	vectors, err := embedder.Embed(ctx, texts)
+	require.NoError(t, err)
+	require.NoError(t, store.Upsert(ctx,
+		vectorstore.Document{ID: "go-1", Content: texts[0], Vector: vectors[0], Metadata: map[string]any{"source": "go.md", "lang": "go"}},
+		vectorstore.Document{ID: "go-2", Content: texts[1], Vector: vectors[1], Metadata: map[string]any{"source": "go.md", "lang": "go"}},
+		vectorstore.Document{ID: "rust-1", Content: texts[2], Vector: vectors[2], Metadata: map[string]any{"title": "Rust Book", "lang": "rust"}},
+	))
+
+	tool := NewRetrievalTool(embedder, store, 1)
+	result, err := tool.Call(ctx, `{"query": "lightweight threads in the Go runtime"}`)
+	require.NoError(t, err)
+	require.Contains(t, result, "[1] Source: go.md")
+	require.Contains(t, result, texts[0])
+	require.NotContains(t, result, "[2]")
+
+	result, err = tool.Call(ctx, `{"query": "memory safety", "count": 5, "filter": {"lang": "rust"}}`)
+	require.NoError(t, err)
+	require.Contains(t, result, "[1] Source: Rust Book")
+	require.NotContains(t, result, "go.md")
+
+	// an empty query is reported to the model, which can retry
+	res, err := tool.CallResult(ctx, `{"query": ""}`)
+	require.NoError(t, err)
+	require.True(t, res.IsError)
+}
+
+type topKRetriever struct {
+	topK int
+}
+
+func (r *topKRetriever) Retrieve(ctx context.Context, query string, options ...vectorstore.SearchOption) ([]vectorstore.Result, error) {
+	r.topK = vectorstore.NewSearchOptions(options...).TopK
+	return nil, nil
+}
+
+func TestRetrievalTool_Count(t *testing.T) {
+	r := &topKRetriever{}
+	tool := NewRetrieverTool(r, 4)
+
+	for count, expected := range map[int]int{0: 4, 7: 7, 20: 20, 50: 20} {
+		_, err := tool.Call(context.TODO(), fmt.Sprintf(`{"query": "go", "count": %d}`, count))
+		require.NoError(t, err)
+		require.Equal(t, expected, r.topK, "count %d", count)
+	}
+
+	// the advertised default respects the cap
+	schema := NewRetrieverTool(r, 50).ParameterSchema()
+	require.Equal(t, 20, schema["properties"].(map[string]interface{})["count"].(map[string]interface{})["default"])
+}
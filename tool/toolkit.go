@@ -0,0 +1,69 @@
+package tool
+
+import "context"
+
+// Toolkit is a named group of tools. The tools are exposed with the toolkit's prefix,
+// by default its name followed by an underscore, so toolkits offering tools with the
+// same name, e.g. two MCP servers with a "search" tool, can be used side by side.
+type Toolkit struct {
+	name   string
+	prefix string
+	tools  []Tool
+}
+
+// NewToolkit creates a toolkit named name containing tools.
+func NewToolkit(name string, tools ...Tool) *Toolkit {
+	return &Toolkit{name: name, prefix: name + "_", tools: tools}
+}
+
+// WithPrefix replaces the name prefix of the toolkit's tools; an empty prefix keeps their names.
+func (k *Toolkit) WithPrefix(prefix string) *Toolkit {
+	k.prefix = prefix
+	return k
+}
+
+// Add adds tools to the toolkit.
+func (k *Toolkit) Add(tools ...Tool) *Toolkit {
+	k.tools = append(k.tools, tools...)
+	return k
+}
+
+// Name returns the name of the toolkit.
+func (k *Toolkit) Name() string {
+	return k.name
+}
+
+// Tools returns the tools of the toolkit with their prefixed names.
+func (k *Toolkit) Tools() []Tool {
+	tools := make([]Tool, 0, len(k.tools))
+	for _, t := range k.tools {
+		tools = append(tools, Prefixed(k.prefix, t))
+	}
+	return tools
+}
+
+// Prefixed returns t exposed under prefix + t.Name(). Rich results of a ResultTool are preserved.
+// The returned tool has an Unwrap method returning t.
+func Prefixed(prefix string, t Tool) Tool {
+	if prefix == "" {
+		return t
+	}
+	return &prefixed{Tool: t, name: prefix + t.Name()}
+}
+
+type prefixed struct {
+	Tool
+	name string
+}
+
+func (p *prefixed) Name() string {
+	return p.name
+}
+
+func (p *prefixed) Unwrap() Tool {
+	return p.Tool
+}
+
+func (p *prefixed) CallResult(ctx context.Context, params string) (*Result, error) {
+	return Call(ctx, p.Tool, params)
+}
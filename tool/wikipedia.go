@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
 
 	"github.com/tidwall/gjson"
@@ -25,11 +26,17 @@ type WikiParams struct {
 type WikipediaTool struct {
 	topK     int
 	language string
+	api      httpConfig
 }
 
-// NewWikipediaTool creates a new instance of WikipediaTool
-func NewWikipediaTool(topK int, language string) *WikipediaTool {
-	return &WikipediaTool{topK: topK, language: language}
+// NewWikipediaTool creates a new instance of WikipediaTool searching the Wikipedia of the
+// given language, unless WithAPIBaseURL points it to another MediaWiki api.php endpoint.
+func NewWikipediaTool(topK int, language string, options ...HTTPOption) *WikipediaTool {
+	return &WikipediaTool{
+		topK:     topK,
+		language: language,
+		api:      newHTTPConfig(fmt.Sprintf("https://%s.wikipedia.org/w/api.php", language), "WikipediaTool/1.0", options),
+	}
 }
 
 // ParameterSchema function the parameters structure for a Wiki search query
@@ -79,8 +86,7 @@ func (b *WikipediaTool) Call(ctx context.Context, params string) (string, error)
 	}
 
 	// Build the request URL
-	baseURL := fmt.Sprintf("https://%s.wikipedia.org/w/api.php", b.language)
-	u, err := url.Parse(baseURL)
+	u, err := url.Parse(b.api.baseURL)
 	if err != nil {
 		return "", err
 	}
@@ -110,14 +116,13 @@ func (b *WikipediaTool) Call(ctx context.Context, params string) (string, error)
 
 	// Add necessary headers
 	req.Header.Add("Accept", "application/json")
-	req.Header.Add("User-Agent", "WikipediaTool/1.0")
 
 	// Execute the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, cancel, err := b.api.do(req)
 	if err != nil {
 		return "", err
 	}
+	defer cancel()
 	defer resp.Body.Close()
 
 	// Check for errors
@@ -143,11 +148,14 @@ func (b *WikipediaTool) Call(ctx context.Context, params string) (string, error)
 		return "No results found.", nil
 	}
 
-	// Process the results
+	// Process the results in search rank order
 	pageIDs := make([]string, 0, len(pages))
 	for pageID := range pages {
 		pageIDs = append(pageIDs, pageID)
 	}
+	sort.Slice(pageIDs, func(i, j int) bool {
+		return pages[pageIDs[i]].Get("index").Int() < pages[pageIDs[j]].Get("index").Int()
+	})
 
 	for i, pageID := range pageIDs {
 		result := pages[pageID]
@@ -2,15 +2,62 @@ package tool
 
 import (
 	"context"
-	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+const wikipediaFixture = `{
+	"query": {
+		"pages": {
+			"2104": {"pageid": 2104, "index": 2, "title": "Changdeokgung", "extract": "Changdeokgung is a palace in Seoul.", "fullurl": "https://en.wikipedia.org/wiki/Changdeokgung"},
+			"97": {"pageid": 97, "index": 1, "title": "Gyeongbokgung", "extract": "Gyeongbokgung is the main royal palace of the Joseon dynasty.", "fullurl": "https://en.wikipedia.org/wiki/Gyeongbokgung"}
+		}
+	}
+}`
+
 func TestWikipediaTool_Call(t *testing.T) {
-	tool := NewWikipediaTool(3, "ko")
-	result, err := tool.Call(context.TODO(), "{\"query\": \"경복궁\"}")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/w/api.php", r.URL.Path)
+		assert.Equal(t, "경복궁", r.URL.Query().Get("gsrsearch"))
+		assert.Equal(t, "10", r.URL.Query().Get("gsrlimit"))
+		assert.Equal(t, "WikipediaTool/1.0", r.Header.Get("User-Agent"))
+		w.Write([]byte(wikipediaFixture))
+	}))
+	defer server.Close()
+
+	tool := NewWikipediaTool(3, "ko", WithAPIBaseURL(server.URL+"/w/api.php"), WithAPIHTTPClient(server.Client()))
+	result, err := tool.Call(context.TODO(), `{"query": "경복궁"}`)
 	require.NoError(t, err)
-	fmt.Println(result)
+	require.Equal(t, `Wikipedia results for '경복궁':
+
+1. Gyeongbokgung
+   URL: https://en.wikipedia.org/wiki/Gyeongbokgung
+   Gyeongbokgung is the main royal palace of the Joseon dynasty.
+
+2. Changdeokgung
+   URL: https://en.wikipedia.org/wiki/Changdeokgung
+   Changdeokgung is a palace in Seoul.
+
+`, result)
+}
+
+func TestWikipediaTool_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "my-agent/2.0", r.Header.Get("User-Agent"))
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	tool := NewWikipediaTool(3, "en", WithAPIBaseURL(server.URL), WithAPIUserAgent("my-agent/2.0"), WithAPITimeout(20*time.Millisecond))
+	_, err := tool.Call(context.TODO(), `{"query": "Seoul"}`)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.True(t, IsTransient(err))
 }
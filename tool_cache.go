@@ -0,0 +1,143 @@
+package gothought
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// ToolCache stores tool results by a key made of the tool name and its canonical
+// arguments, so calling a tool again with the same arguments reuses the earlier result.
+// See WithToolCache and WithRunToolCache.
+type ToolCache interface {
+	// Get looks up the result stored under key.
+	// The boolean result reports whether a result was found.
+	Get(ctx context.Context, key string) (*tool.Result, bool, error)
+
+	// Set stores the result of a successful call under key.
+	Set(ctx context.Context, key string, result *tool.Result) error
+}
+
+// MemoryToolCache is an in-memory ToolCache whose entries expire after a fixed TTL.
+type MemoryToolCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]toolCacheEntry
+	now     func() time.Time
+}
+
+type toolCacheEntry struct {
+	result  *tool.Result
+	expires time.Time
+}
+
+// NewMemoryToolCache creates a MemoryToolCache keeping results for ttl, or forever if ttl is 0.
+func NewMemoryToolCache(ttl time.Duration) *MemoryToolCache {
+	return &MemoryToolCache{
+		ttl:     ttl,
+		entries: map[string]toolCacheEntry{},
+		now:     time.Now,
+	}
+}
+
+// Get returns the result stored under key unless it has expired.
+func (m *MemoryToolCache) Get(ctx context.Context, key string) (*tool.Result, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expires.IsZero() && !m.now().Before(entry.expires) {
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	return entry.result, true, nil
+}
+
+// Set stores result under key.
+func (m *MemoryToolCache) Set(ctx context.Context, key string, result *tool.Result) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := toolCacheEntry{result: result}
+	if m.ttl > 0 {
+		entry.expires = m.now().Add(m.ttl)
+	}
+	m.entries[key] = entry
+	return nil
+}
+
+// Len returns the number of stored results, including expired ones not evicted yet.
+func (m *MemoryToolCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.entries)
+}
+
+// toolCaching configures which tool results are reused.
+type toolCaching struct {
+	// cache is shared by all queries, nil when results are only reused within a run
+	cache ToolCache
+	// tools are the cached tools, nil for all tools
+	tools map[string]bool
+}
+
+// cachedTools returns the set of names, nil for all tools.
+func cachedTools(names []string) map[string]bool {
+	if len(names) == 0 {
+		return nil
+	}
+	tools := make(map[string]bool, len(names))
+	for _, name := range names {
+		tools[name] = true
+	}
+	return tools
+}
+
+// runCache returns the cache used by one run of the agent loop, or nil if caching is disabled.
+func (c *toolCaching) runCache() ToolCache {
+	if c == nil {
+		return nil
+	}
+	if c.cache != nil {
+		return c.cache
+	}
+	return NewMemoryToolCache(0)
+}
+
+// caches reports whether results of the named tool are cached.
+func (c *toolCaching) caches(name string) bool {
+	return c != nil && (c.tools == nil || c.tools[name])
+}
+
+// toolCacheKey returns the cache key of a call, with the arguments in canonical form
+// so that formatting and key order do not matter.
+func toolCacheKey(call ToolCalls) string {
+	return call.Function.Name + "\x00" + canonicalArguments(call.Function.Arguments)
+}
+
+func canonicalArguments(raw string) string {
+	if strings.TrimSpace(raw) == "" {
+		raw = "{}"
+	}
+
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+
+	var args any
+	if err := dec.Decode(&args); err != nil {
+		return raw
+	}
+	// maps are encoded with sorted keys
+	bt, err := json.Marshal(args)
+	if err != nil {
+		return raw
+	}
+	return string(bt)
+}
@@ -0,0 +1,88 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguageModel_RunToolCache(t *testing.T) {
+	search := &fakeTool{name: "search", result: "Go is a language."}
+	clock := &fakeTool{name: "now", result: "12:00"}
+
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{
+			toolCall("1", "search", `{"q": "go", "n": 1}`),
+			toolCall("2", "search", `{"n":1,"q":"go"}`),
+			toolCall("3", "now", `{}`),
+			toolCall("4", "now", `{}`),
+		}},
+		{Role: "assistant", ToolCalls: []ToolCalls{toolCall("5", "search", `{"q": "go", "n": 1}`)}},
+		{Message: "done"},
+	}}
+
+	model := NewLanguageModel(provider, WithRunToolCache("search")).AddTool(search).AddTool(clock).HumanPrompt("hi")
+	_, err := model.Q(context.TODO())
+	require.NoError(t, err)
+
+	// duplicate calls in the same turn and in later turns are answered from the cache
+	require.Equal(t, 1, search.calls)
+	require.Equal(t, 2, clock.calls)
+	for _, i := range []int{2, 3, 7} {
+		require.Equal(t, "Go is a language.", provider.messages[i].Message)
+	}
+
+	// the cache does not outlive the query
+	provider.calls = 0
+	_, err = model.Q(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 2, search.calls)
+}
+
+func TestLanguageModel_ToolCache(t *testing.T) {
+	search := &fakeTool{name: "search", result: "Go is a language."}
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{toolCall("1", "search", `{"q": "go"}`)}},
+		{Message: "done"},
+	}}
+
+	cache := NewMemoryToolCache(0)
+	model := NewLanguageModel(provider, WithToolCache(cache)).AddTool(search).HumanPrompt("hi")
+	for range 2 {
+		provider.calls = 0
+		_, err := model.Q(context.TODO())
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, search.calls)
+	require.Equal(t, 1, cache.Len())
+
+	// error results are not cached
+	provider.responses[0].ToolCalls = []ToolCalls{toolCall("1", "browse", `{}`)}
+	provider.calls = 0
+	_, err := model.Q(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, 1, cache.Len())
+}
+
+func TestMemoryToolCache_TTL(t *testing.T) {
+	ctx := context.TODO()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := NewMemoryToolCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	require.NoError(t, cache.Set(ctx, "search", tool.TextResult("Go")))
+
+	res, ok, err := cache.Get(ctx, "search")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, "Go", res.Text)
+
+	now = now.Add(time.Minute)
+	_, ok, err = cache.Get(ctx, "search")
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.Zero(t, cache.Len())
+}
@@ -0,0 +1,163 @@
+package gothought
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gobenpark/gothought/jsonschema"
+	"github.com/gobenpark/gothought/tool"
+)
+
+// callTool executes a single tool call requested by the model and returns its result.
+// Calls the model can fix itself, such as an unknown tool name or arguments that do not
+// satisfy the tool's ParameterSchema, are answered with an error result instead of invoking
+// the tool, so the model gets a chance to correct the call.
+func (l *LanguageModel) callTool(ctx context.Context, tools map[string]tool.Tool, call ToolCalls) (*tool.Result, error) {
+	t, ok := tools[call.Function.Name]
+	if !ok {
+		names := make([]string, 0, len(tools))
+		for name := range tools {
+			names = append(names, name)
+		}
+		return tool.ErrorResult("unknown tool %q. Available tools: %s.", call.Function.Name, strings.Join(names, ", ")), nil
+	}
+
+	args, err := prepareArguments(t.ParameterSchema(), call.Function.Arguments)
+	if err != nil {
+		return tool.ErrorResult("invalid arguments for tool %q: %v. Fix the arguments and call the tool again.", call.Function.Name, err), nil
+	}
+
+	return l.executeTool(ctx, t, args)
+}
+
+// callTools executes the tool calls of one response, up to toolConcurrency at a time,
+// and returns their results in the order of calls. The first error cancels the remaining calls.
+// Results of cached tools are looked up in cache, and identical calls of cached tools in
+// the same response are executed only once.
+func (l *LanguageModel) callTools(ctx context.Context, tools map[string]tool.Tool, calls []ToolCalls, cache ToolCache) ([]*tool.Result, error) {
+	results := make([]*tool.Result, len(calls))
+
+	var pending []int
+	duplicates := map[int]int{}
+	first := map[string]int{}
+	for i, call := range calls {
+		if cache != nil && l.toolCaching.caches(call.Function.Name) {
+			key := toolCacheKey(call)
+			if j, ok := first[key]; ok {
+				duplicates[i] = j
+				continue
+			}
+			first[key] = i
+		}
+		pending = append(pending, i)
+	}
+
+	if l.toolConcurrency <= 1 || len(pending) == 1 {
+		for _, i := range pending {
+			res, err := l.callCached(ctx, tools, calls[i], cache)
+			if err != nil {
+				return nil, err
+			}
+			results[i] = res
+		}
+	} else {
+		ctx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		sem := make(chan struct{}, l.toolConcurrency)
+		var wg sync.WaitGroup
+		for _, i := range pending {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+					return
+				}
+
+				res, err := l.callCached(ctx, tools, calls[i], cache)
+				if err != nil {
+					cancel(err)
+					return
+				}
+				results[i] = res
+			}()
+		}
+		wg.Wait()
+
+		if err := context.Cause(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, j := range duplicates {
+		results[i] = results[j]
+	}
+	return results, nil
+}
+
+// callCached is callTool looking up and storing the results of cached tools in cache.
+// Error results are not stored, so failed calls are tried again.
+func (l *LanguageModel) callCached(ctx context.Context, tools map[string]tool.Tool, call ToolCalls, cache ToolCache) (*tool.Result, error) {
+	if cache == nil || !l.toolCaching.caches(call.Function.Name) {
+		return l.callTool(ctx, tools, call)
+	}
+
+	key := toolCacheKey(call)
+	cached, ok, err := cache.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return cached, nil
+	}
+
+	res, err := l.callTool(ctx, tools, call)
+	if err != nil || res.IsError {
+		return res, err
+	}
+	return res, cache.Set(ctx, key, res)
+}
+
+// prepareArguments decodes the raw arguments of a tool call, applies the defaults declared
+// in the tool's parameter schema and validates the result against it.
+func prepareArguments(parameters map[string]interface{}, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		raw = "{}"
+	}
+
+	// keep numbers as written so large integers survive re-encoding
+	dec := json.NewDecoder(strings.NewReader(raw))
+	dec.UseNumber()
+
+	var args any
+	if err := dec.Decode(&args); err != nil {
+		return "", fmt.Errorf("arguments are not valid JSON: %v", err)
+	}
+
+	if len(parameters) == 0 {
+		return raw, nil
+	}
+
+	schema, err := jsonschema.FromMap(parameters)
+	if err != nil {
+		// a schema we cannot interpret must not block the tool
+		return raw, nil
+	}
+
+	args = schema.ApplyDefaults(args)
+	if err := schema.Validate(args); err != nil {
+		return "", err
+	}
+
+	bt, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return string(bt), nil
+}
@@ -0,0 +1,77 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/stretchr/testify/require"
+)
+
+func toolCall(id, name, arguments string) ToolCalls {
+	call := ToolCalls{ID: id, Type: "function"}
+	call.Function.Name = name
+	call.Function.Arguments = arguments
+	return call
+}
+
+func TestLanguageModel_ToolArgumentValidation(t *testing.T) {
+	search := &fakeTool{name: "search", result: "results", schema: map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"query": map[string]interface{}{"type": "string"},
+			"count": map[string]interface{}{"type": "integer", "default": 10},
+		},
+		"required": []string{"query"},
+	}}
+
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{
+			toolCall("1", "search", `{"count": "five"}`),
+			toolCall("2", "browse", `{}`),
+			toolCall("3", "search", `{"query": "go", "id": 12345678901234567890}`),
+		}},
+		{Message: "done"},
+	}}
+
+	_, err := NewLanguageModel(provider).AddTool(search).HumanPrompt("find go").Q(context.TODO())
+	require.NoError(t, err)
+
+	// only the valid call reaches the tool, with defaults applied
+	require.Len(t, search.params, 1)
+	require.JSONEq(t, `{"query": "go", "count": 10, "id": 12345678901234567890}`, search.params[0])
+
+	results := provider.messages[2:]
+	require.Contains(t, results[0].Message, `invalid arguments for tool "search"`)
+	require.Contains(t, results[0].Message, `missing required property "query"`)
+	require.Contains(t, results[0].Message, `/count: expected integer, got string`)
+	require.Contains(t, results[1].Message, `unknown tool "browse"`)
+	require.Equal(t, "results", results[2].Message)
+}
+
+func TestLanguageModel_ToolResultHandler(t *testing.T) {
+	lookup := tool.NewFunc("lookup", "Looks up a city", func(ctx context.Context, p struct {
+		City string `json:"city"`
+	}) (*tool.Result, error) {
+		return &tool.Result{Text: "Seoul is the capital.", Metadata: map[string]any{"population": 9411000}}, nil
+	})
+
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{toolCall("1", "lookup", `{"city": "Seoul"}`)}},
+		{Message: "done"},
+	}}
+
+	var results []*tool.Result
+	handler := func(ctx context.Context, call ToolCalls, result *tool.Result) {
+		require.Equal(t, "1", call.ID)
+		results = append(results, result)
+	}
+
+	_, err := NewLanguageModel(provider, WithToolResultHandler(handler)).AddTool(lookup).HumanPrompt("tell me about Seoul").Q(context.TODO())
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	require.Equal(t, 9411000, results[0].Metadata["population"])
+	// metadata stays with the caller
+	require.Equal(t, "Seoul is the capital.", provider.messages[2].Message)
+}
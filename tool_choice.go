@@ -0,0 +1,130 @@
+package gothought
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// ToolChoiceMode controls whether the model may or must call tools.
+type ToolChoiceMode string
+
+const (
+	// ToolChoiceAuto lets the model decide whether to call tools.
+	ToolChoiceAuto ToolChoiceMode = "auto"
+	// ToolChoiceNone forbids tool calls.
+	ToolChoiceNone ToolChoiceMode = "none"
+	// ToolChoiceRequired makes the model call at least one tool.
+	ToolChoiceRequired ToolChoiceMode = "required"
+	// ToolChoiceFunction makes the model call the tool named in ToolChoice.Name.
+	ToolChoiceFunction ToolChoiceMode = "function"
+)
+
+// ToolChoice is the tool choice of a single request. The zero value is ToolChoiceAuto.
+type ToolChoice struct {
+	Mode ToolChoiceMode
+	// Name is the tool to call with ToolChoiceFunction.
+	Name string
+}
+
+var (
+	// AutoToolChoice lets the model decide whether to call tools.
+	AutoToolChoice = ToolChoice{Mode: ToolChoiceAuto}
+	// NoToolChoice forbids tool calls.
+	NoToolChoice = ToolChoice{Mode: ToolChoiceNone}
+	// RequiredToolChoice makes the model call at least one tool.
+	RequiredToolChoice = ToolChoice{Mode: ToolChoiceRequired}
+)
+
+// ForceTool returns the tool choice making the model call the tool name.
+func ForceTool(name string) ToolChoice {
+	return ToolChoice{Mode: ToolChoiceFunction, Name: name}
+}
+
+func (c ToolChoice) isAuto() bool {
+	return c.Mode == "" || c.Mode == ToolChoiceAuto
+}
+
+// ErrToolChoiceUnsupported is returned when a tool choice other than auto or none is
+// requested from a provider that does not implement ToolChoiceCapable.
+var ErrToolChoiceUnsupported = errors.New("provider does not support tool choice")
+
+// WithToolChoice sets the tool choice of the query. Since a model forced to call tools
+// would never give a final answer, ToolChoiceRequired and ToolChoiceFunction only apply
+// until the model has called a tool; later iterations of the agent loop use auto.
+// Use WithToolChoiceFunc for full control over every iteration.
+//
+//	// force the extraction tool
+//	res, err := model.Q(ctx, gothought.WithToolChoice(gothought.ForceTool("extract_invoice")))
+func WithToolChoice(choice ToolChoice) CallOption {
+	return func(c *callConfig) {
+		c.toolChoice = func(iteration, maxIterations int, called bool) ToolChoice {
+			if called && (choice.Mode == ToolChoiceRequired || choice.Mode == ToolChoiceFunction) {
+				return AutoToolChoice
+			}
+			return choice
+		}
+	}
+}
+
+// WithToolChoiceFunc chooses the tool choice for every iteration of the agent loop.
+// iteration starts at 0 and the loop fails after maxIterations iterations.
+//
+//	// forbid tools on the final step so the model has to answer
+//	gothought.WithToolChoiceFunc(func(iteration, maxIterations int) gothought.ToolChoice {
+//		if iteration == maxIterations-1 {
+//			return gothought.NoToolChoice
+//		}
+//		return gothought.AutoToolChoice
+//	})
+func WithToolChoiceFunc(fn func(iteration, maxIterations int) ToolChoice) CallOption {
+	return func(c *callConfig) {
+		c.toolChoice = func(iteration, maxIterations int, called bool) ToolChoice {
+			return fn(iteration, maxIterations)
+		}
+	}
+}
+
+// WithParallelToolCalls allows or forbids the model to request several tool calls in one response.
+func WithParallelToolCalls(enabled bool) CallOption {
+	return func(c *callConfig) {
+		c.parallelToolCalls = &enabled
+	}
+}
+
+// stepProvider returns the provider and tools to use for an iteration of the agent loop.
+// called reports whether the model already called a tool in an earlier iteration.
+func (l *LanguageModel) stepProvider(cfg *callConfig, tools map[string]tool.Tool, iteration int, called bool) (Provider, map[string]tool.Tool, error) {
+	choice := AutoToolChoice
+	if cfg.toolChoice != nil {
+		choice = cfg.toolChoice(iteration, l.maxIterations, called)
+	}
+	if choice.isAuto() && cfg.parallelToolCalls == nil {
+		return l.provider, tools, nil
+	}
+
+	if choice.Mode == ToolChoiceFunction {
+		if _, ok := tools[choice.Name]; !ok {
+			return nil, nil, fmt.Errorf("%w: %q", ErrUnknownTool, choice.Name)
+		}
+	}
+	if (choice.Mode == ToolChoiceRequired || choice.Mode == ToolChoiceFunction) && len(tools) == 0 {
+		return nil, nil, fmt.Errorf("tool choice %q requires tools", choice.Mode)
+	}
+
+	if p, ok := l.provider.(ToolChoiceCapable); ok {
+		return p.WithToolChoice(choice, cfg.parallelToolCalls), tools, nil
+	}
+
+	switch {
+	case choice.Mode == ToolChoiceNone:
+		// not offering any tools has the same effect
+		return l.provider, nil, nil
+	case choice.isAuto():
+		// parallel tool calls are a hint the provider cannot honour
+		return l.provider, tools, nil
+	default:
+		return nil, nil, ErrToolChoiceUnsupported
+	}
+}
@@ -0,0 +1,88 @@
+package gothought
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func TestOpenAIProvider_ToolChoice(t *testing.T) {
+	var bodies []gjson.Result
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bt, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, gjson.ParseBytes(bt))
+
+		if len(bodies) == 1 {
+			fmt.Fprint(w, `{"choices": [{"finish_reason": "tool_calls", "message": {"role": "assistant", "tool_calls": [
+				{"id": "call_1", "type": "function", "function": {"name": "lookup", "arguments": "{}"}}]}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"choices": [{"finish_reason": "stop", "message": {"role": "assistant", "content": "done"}}]}`)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("gpt-4o", "key", 0)
+	provider.baseURL = server.URL
+	model := NewLanguageModel(provider).AddTool(&fakeTool{name: "lookup"}).AddTool(&fakeTool{name: "other"}).HumanPrompt("hi")
+
+	_, err := model.Q(context.TODO(), WithToolChoice(ForceTool("lookup")), WithParallelToolCalls(false))
+	require.NoError(t, err)
+	require.Len(t, bodies, 2)
+
+	// the forced choice applies until the tool was called
+	require.JSONEq(t, `{"type": "function", "function": {"name": "lookup"}}`, bodies[0].Get("tool_choice").Raw)
+	require.Equal(t, "auto", bodies[1].Get("tool_choice").String())
+	require.False(t, bodies[0].Get("parallel_tool_calls").Bool())
+	require.True(t, bodies[0].Get("parallel_tool_calls").Exists())
+
+	// without tools neither tool_choice nor tools are sent
+	bodies = nil
+	_, err = NewLanguageModel(provider).HumanPrompt("hi").Q(context.TODO(), WithToolChoice(NoToolChoice))
+	require.NoError(t, err)
+	require.False(t, bodies[0].Get("tool_choice").Exists())
+	require.False(t, bodies[0].Get("tools").Exists())
+}
+
+func TestLanguageModel_ToolChoiceFallback(t *testing.T) {
+	ctx := context.TODO()
+	call := toolCall("1", "lookup", `{}`)
+	provider := &fakeProvider{responses: []Message{{Role: "assistant", ToolCalls: []ToolCalls{call}}}}
+	model := NewLanguageModel(provider, WithIteration(3)).AddTool(&fakeTool{name: "lookup"}).HumanPrompt("hi")
+
+	// providers without tool choice support get no tools instead of tool_choice none
+	_, err := model.Q(ctx, WithToolChoiceFunc(func(iteration, maxIterations int) ToolChoice {
+		if iteration == maxIterations-1 {
+			return NoToolChoice
+		}
+		return AutoToolChoice
+	}))
+	require.ErrorContains(t, err, "max iterations reached")
+	require.Empty(t, provider.tools)
+
+	_, err = model.Q(ctx, WithToolChoice(RequiredToolChoice))
+	require.ErrorIs(t, err, ErrToolChoiceUnsupported)
+
+	_, err = model.Q(ctx, WithToolChoice(ForceTool("missing")))
+	require.ErrorIs(t, err, ErrUnknownTool)
+}
+
+func TestOpenAIToolChoice(t *testing.T) {
+	for choice, want := range map[ToolChoice]string{
+		{}:                 `"auto"`,
+		NoToolChoice:       `"none"`,
+		RequiredToolChoice: `"required"`,
+		ForceTool("x"):     `{"function":{"name":"x"},"type":"function"}`,
+	} {
+		bt, err := json.Marshal(openAIToolChoice(choice))
+		require.NoError(t, err)
+		require.Equal(t, want, string(bt))
+	}
+}
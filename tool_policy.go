@@ -0,0 +1,223 @@
+package gothought
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gobenpark/gothought/tool"
+)
+
+// TruncationStrategy decides how a tool result longer than ToolPolicy.MaxResultLength is shortened.
+type TruncationStrategy int
+
+const (
+	// TruncateHead keeps the beginning of the result.
+	TruncateHead TruncationStrategy = iota
+	// TruncateTail keeps the end of the result, e.g. for logs.
+	TruncateTail
+	// TruncateSummarize asks the model to summarise the result, falling back to TruncateHead
+	// when the summary is still too long or cannot be generated.
+	TruncateSummarize
+)
+
+// ToolPolicy controls how the agent loop executes a tool. The zero value calls the tool
+// once, without timeout, concurrency limit or result limit.
+type ToolPolicy struct {
+	// Timeout bounds every attempt. A call that times out on its last attempt is reported
+	// to the model as an error result, so the agent can continue without it.
+	Timeout time.Duration
+
+	// Retries is the number of additional attempts after a transient failure.
+	Retries int
+	// Backoff is the delay before the first retry, doubling for every further retry. Default 200ms.
+	Backoff time.Duration
+	// Retryable reports whether a failure is transient, default tool.IsTransient.
+	Retryable func(err error) bool
+
+	// MaxResultLength is the maximum number of characters of the text and JSON of a result sent
+	// to the model, including the truncation marker; 0 is unlimited.
+	MaxResultLength int
+	// Truncation is how longer results are shortened.
+	Truncation TruncationStrategy
+
+	// MaxConcurrency limits how many calls of the tool run at the same time across all
+	// queries of the model, 0 is unlimited.
+	MaxConcurrency int
+}
+
+const defaultBackoff = 200 * time.Millisecond
+
+// policyFor returns the policy of the named tool.
+func (l *LanguageModel) policyFor(name string) ToolPolicy {
+	if p, ok := l.toolPolicies[name]; ok {
+		return p
+	}
+	return l.defaultToolPolicy
+}
+
+// toolLimiter holds the semaphores of tools with a MaxConcurrency.
+type toolLimiter struct {
+	mu   sync.Mutex
+	sems map[string]chan struct{}
+}
+
+func (t *toolLimiter) acquire(ctx context.Context, name string, limit int) (func(), error) {
+	if limit <= 0 {
+		return func() {}, nil
+	}
+
+	t.mu.Lock()
+	if t.sems == nil {
+		t.sems = map[string]chan struct{}{}
+	}
+	sem, ok := t.sems[name]
+	if !ok {
+		sem = make(chan struct{}, limit)
+		t.sems[name] = sem
+	}
+	t.mu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// executeTool calls t applying the policy of the tool: concurrency limit, per-attempt timeout,
+// retries with exponential backoff and the result length limit.
+func (l *LanguageModel) executeTool(ctx context.Context, t tool.Tool, args string) (*tool.Result, error) {
+	policy := l.policyFor(t.Name())
+
+	release, err := l.limiter.acquire(ctx, t.Name(), policy.MaxConcurrency)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = tool.IsTransient
+	}
+	backoff := policy.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+
+	var res *tool.Result
+	for attempt := 0; ; attempt++ {
+		res, err = callWithTimeout(ctx, t, args, policy.Timeout)
+		if err == nil || ctx.Err() != nil || attempt >= policy.Retries || !retryable(err) {
+			break
+		}
+
+		select {
+		case <-time.After(backoff << attempt):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err != nil {
+		if policy.Timeout > 0 && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return tool.ErrorResult("tool %q timed out after %s", t.Name(), policy.Timeout), nil
+		}
+		return nil, err
+	}
+	return l.limitResult(ctx, t.Name(), res, policy), nil
+}
+
+func callWithTimeout(ctx context.Context, t tool.Tool, args string, timeout time.Duration) (*tool.Result, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return tool.Call(ctx, t, args)
+}
+
+// limitResult shortens a result whose text and JSON payload exceed the policy's MaxResultLength.
+// The JSON payload is folded into the shortened text; artifacts, which are not counted, and
+// metadata are kept.
+func (l *LanguageModel) limitResult(ctx context.Context, name string, res *tool.Result, policy ToolPolicy) *tool.Result {
+	limit := policy.MaxResultLength
+	content := (&tool.Result{Text: res.Text, JSON: res.JSON}).Content()
+	if res.IsError {
+		// Content puts the prefix back in front of the shortened text
+		content = strings.TrimPrefix(content, errorPrefix)
+		limit -= utf8.RuneCountInString(errorPrefix)
+	}
+	if policy.MaxResultLength <= 0 || utf8.RuneCountInString(content) <= limit {
+		return res
+	}
+
+	var text string
+	switch policy.Truncation {
+	case TruncateTail:
+		text = truncateTail(content, limit)
+	case TruncateSummarize:
+		summary, err := l.summarizeResult(ctx, name, content, limit)
+		if err != nil || utf8.RuneCountInString(summary) > limit {
+			text = truncateHead(content, limit)
+		} else {
+			text = summary
+		}
+	default:
+		text = truncateHead(content, limit)
+	}
+	return &tool.Result{Text: text, Artifacts: res.Artifacts, IsError: res.IsError, Metadata: res.Metadata}
+}
+
+const (
+	errorPrefix = "Error: "
+	headMarker  = "\n[... %d characters truncated]"
+	tailMarker  = "[%d characters truncated ...]\n"
+)
+
+func truncateHead(s string, limit int) string {
+	runes := []rune(s)
+	keep := keptLength(len(runes), limit, headMarker)
+	return string(runes[:keep]) + fmt.Sprintf(headMarker, len(runes)-keep)
+}
+
+func truncateTail(s string, limit int) string {
+	runes := []rune(s)
+	keep := keptLength(len(runes), limit, tailMarker)
+	return fmt.Sprintf(tailMarker, len(runes)-keep) + string(runes[len(runes)-keep:])
+}
+
+// keptLength returns how many of total characters fit within limit together with the
+// truncation marker. Only the marker is left when limit is shorter than the marker itself.
+func keptLength(total, limit int, marker string) int {
+	keep := limit
+	for {
+		next := max(0, limit-utf8.RuneCountInString(fmt.Sprintf(marker, total-keep)))
+		if next == keep {
+			return keep
+		}
+		keep = next
+	}
+}
+
+// summarizeResult asks the model for a summary of a tool result shorter than limit characters.
+func (l *LanguageModel) summarizeResult(ctx context.Context, name, content string, limit int) (string, error) {
+	messages := []Message{
+		{Role: "system", Message: fmt.Sprintf("Summarise the output of the tool %q in at most %d characters. "+
+			"Keep all facts, names, numbers and identifiers a reader may need; do not add anything.", name, limit)},
+		{Role: "user", Message: content},
+	}
+	res, _, err := l.provider.Generate(ctx, nil, messages)
+	if err != nil {
+		return "", err
+	}
+	if res == nil {
+		return "", errors.New("empty summary")
+	}
+	return res.Message, nil
+}
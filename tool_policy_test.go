@@ -0,0 +1,189 @@
+package gothought
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gobenpark/gothought/tool"
+	"github.com/stretchr/testify/require"
+)
+
+type echoParams struct {
+	Text string `json:"text,omitempty"`
+}
+
+func TestLanguageModel_ToolTimeout(t *testing.T) {
+	slow := tool.NewFunc("slow", "Never answers", func(ctx context.Context, p echoParams) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{toolCall("1", "slow", `{}`)}},
+		{Message: "done"},
+	}}
+
+	model := NewLanguageModel(provider, WithToolPolicy("slow", ToolPolicy{Timeout: 20 * time.Millisecond}))
+	res, err := model.AddTool(slow).HumanPrompt("hi").Q(context.TODO())
+	require.NoError(t, err)
+	require.Equal(t, "done", res.Message)
+	require.True(t, provider.messages[2].ToolResult.IsError)
+	require.Contains(t, provider.messages[2].Message, `tool "slow" timed out after 20ms`)
+}
+
+func TestLanguageModel_ToolRetries(t *testing.T) {
+	var attempts atomic.Int32
+	flaky := tool.NewFunc("flaky", "Fails twice", func(ctx context.Context, p echoParams) (string, error) {
+		if attempts.Add(1) < 3 {
+			return "", tool.Transient(errors.New("service unavailable"))
+		}
+		return "ok", nil
+	})
+	broken := tool.NewFunc("broken", "Always fails", func(ctx context.Context, p echoParams) (string, error) {
+		return "", errors.New("bad request")
+	})
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: []ToolCalls{toolCall("1", "flaky", `{}`)}},
+		{Message: "done"},
+	}}
+
+	model := NewLanguageModel(provider, WithDefaultToolPolicy(ToolPolicy{Retries: 2, Backoff: time.Millisecond}))
+	_, err := model.AddTool(flaky).AddTool(broken).HumanPrompt("hi").Q(context.TODO())
+	require.NoError(t, err)
+	require.EqualValues(t, 3, attempts.Load())
+	require.Equal(t, "ok", provider.messages[2].Message)
+
+	// permanent errors are not retried
+	provider.responses[0].ToolCalls = []ToolCalls{toolCall("1", "broken", `{}`)}
+	provider.calls = 0
+	_, err = model.Q(context.TODO())
+	require.EqualError(t, err, "bad request")
+}
+
+func TestLanguageModel_ToolResultLimit(t *testing.T) {
+	long := strings.Repeat("a", 50) + strings.Repeat("z", 50)
+	chart := tool.Artifact{Name: "chart.png", MIMEType: "image/png", Data: []byte("png")}
+	dump := tool.NewFunc("dump", "Returns a long text", func(ctx context.Context, p echoParams) (*tool.Result, error) {
+		switch p.Text {
+		case "chart":
+			return &tool.Result{Text: long, Artifacts: []tool.Artifact{chart}}, nil
+		case "error":
+			return &tool.Result{Text: strings.Repeat("e", 34), IsError: true}, nil
+		}
+		return tool.TextResult(long), nil
+	})
+
+	for name, tc := range map[string]struct {
+		policy   ToolPolicy
+		args     string
+		summary  string
+		expected string
+	}{
+		"head": {
+			policy:   ToolPolicy{MaxResultLength: 40},
+			expected: "aaaaaaaaaa\n[... 90 characters truncated]",
+		},
+		"tail": {
+			policy:   ToolPolicy{MaxResultLength: 40, Truncation: TruncateTail},
+			expected: "[90 characters truncated ...]\nzzzzzzzzzz",
+		},
+		"shorter than the marker": {
+			policy:   ToolPolicy{MaxResultLength: 10},
+			expected: "\n[... 100 characters truncated]",
+		},
+		"summarize": {
+			policy:   ToolPolicy{MaxResultLength: 40, Truncation: TruncateSummarize},
+			summary:  "a then z",
+			expected: "a then z",
+		},
+		"summary too long": {
+			policy:   ToolPolicy{MaxResultLength: 40, Truncation: TruncateSummarize},
+			summary:  "fifty times the letter a followed by fifty times the letter z",
+			expected: "aaaaaaaaaa\n[... 90 characters truncated]",
+		},
+		"error head": {
+			policy:   ToolPolicy{MaxResultLength: 40},
+			args:     `{"text": "error"}`,
+			expected: "Error: eee\n[... 31 characters truncated]",
+		},
+		"error tail": {
+			policy:   ToolPolicy{MaxResultLength: 40, Truncation: TruncateTail},
+			args:     `{"text": "error"}`,
+			expected: "Error: [31 characters truncated ...]\neee",
+		},
+		"error within the limit": {
+			policy:   ToolPolicy{MaxResultLength: 41},
+			args:     `{"text": "error"}`,
+			expected: "Error: " + strings.Repeat("e", 34),
+		},
+		"artifacts are kept": {
+			policy:   ToolPolicy{MaxResultLength: 40},
+			args:     `{"text": "chart"}`,
+			expected: "aaaaaaaaaa\n[... 90 characters truncated]\n\n[artifact chart.png (image/png, 3 bytes)]",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			args := tc.args
+			if args == "" {
+				args = `{}`
+			}
+			responses := []Message{{Role: "assistant", ToolCalls: []ToolCalls{toolCall("1", "dump", args)}}}
+			if tc.summary != "" {
+				responses = append(responses, Message{Message: tc.summary})
+			}
+			provider := &fakeProvider{responses: append(responses, Message{Message: "done"})}
+
+			var results []*tool.Result
+			handler := func(ctx context.Context, call ToolCalls, result *tool.Result) {
+				results = append(results, result)
+			}
+			_, err := NewLanguageModel(provider, WithToolPolicy("dump", tc.policy), WithToolResultHandler(handler)).
+				AddTool(dump).HumanPrompt("hi").Q(context.TODO())
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, provider.messages[2].Message)
+			require.Len(t, results, 1)
+			if tc.args == `{"text": "chart"}` {
+				require.Equal(t, []tool.Artifact{chart}, results[0].Artifacts)
+			}
+		})
+	}
+}
+
+func TestLanguageModel_ToolConcurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	echo := tool.NewFunc("echo", "Echoes the text", func(ctx context.Context, p echoParams) (string, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			current := peak.Load()
+			if n <= current || peak.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return p.Text, nil
+	})
+
+	calls := make([]ToolCalls, 6)
+	for i := range calls {
+		calls[i] = toolCall(string(rune('a'+i)), "echo", `{"text": "`+string(rune('a'+i))+`"}`)
+	}
+	provider := &fakeProvider{responses: []Message{
+		{Role: "assistant", ToolCalls: calls},
+		{Message: "done"},
+	}}
+
+	model := NewLanguageModel(provider, WithToolConcurrency(4), WithToolPolicy("echo", ToolPolicy{MaxConcurrency: 2}))
+	_, err := model.AddTool(echo).HumanPrompt("hi").Q(context.TODO())
+	require.NoError(t, err)
+	require.EqualValues(t, 2, peak.Load())
+
+	// results keep the order of the calls
+	for i, msg := range provider.messages[2:] {
+		require.Equal(t, calls[i].ID, msg.ToolCallID)
+		require.Equal(t, calls[i].ID, msg.Message)
+	}
+}
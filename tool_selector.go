@@ -0,0 +1,118 @@
+package gothought
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/gobenpark/gothought/embedding"
+	"github.com/gobenpark/gothought/tool"
+)
+
+// ToolSelector narrows down the tools offered to the model for a query, e.g. to keep the
+// request small when hundreds of tools are registered. See WithToolSelector.
+type ToolSelector interface {
+	SelectTools(ctx context.Context, tools map[string]tool.Tool, messages []Message) (map[string]tool.Tool, error)
+}
+
+// EmbeddingToolSelector offers the tools whose name and description are most similar
+// to the last user message. Tool embeddings are computed once and cached.
+type EmbeddingToolSelector struct {
+	embedder embedding.Embedder
+	topK     int
+	always   map[string]bool
+
+	mu      sync.Mutex
+	vectors map[string][]float32 // keyed by the embedded tool text
+}
+
+// NewEmbeddingToolSelector creates a selector offering the topK most relevant tools,
+// plus the tools named in always, which are offered on every query.
+func NewEmbeddingToolSelector(e embedding.Embedder, topK int, always ...string) *EmbeddingToolSelector {
+	s := &EmbeddingToolSelector{
+		embedder: e,
+		topK:     topK,
+		always:   map[string]bool{},
+		vectors:  map[string][]float32{},
+	}
+	for _, name := range always {
+		s.always[name] = true
+	}
+	return s
+}
+
+// SelectTools returns the topK tools most similar to the last user message, and the
+// tools that are always offered. All tools are returned when there is no user message
+// or no more than topK candidates.
+func (s *EmbeddingToolSelector) SelectTools(ctx context.Context, tools map[string]tool.Tool, messages []Message) (map[string]tool.Tool, error) {
+	query, ok := lastUserMessage(messages)
+	if !ok || len(tools) <= s.topK {
+		return tools, nil
+	}
+
+	selected := map[string]tool.Tool{}
+	var names []string
+	for name, t := range tools {
+		if s.always[name] {
+			selected[name] = t
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	vectors, err := s.toolVectors(ctx, tools, names)
+	if err != nil {
+		return nil, err
+	}
+	queryVectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[string]float32, len(names))
+	for _, name := range names {
+		scores[name] = embedding.Cosine(queryVectors[0], vectors[name])
+	}
+	sort.SliceStable(names, func(i, j int) bool {
+		return scores[names[i]] > scores[names[j]]
+	})
+
+	for _, name := range names[:min(s.topK, len(names))] {
+		selected[name] = tools[name]
+	}
+	return selected, nil
+}
+
+// toolVectors returns the embeddings of the named tools, embedding the ones not cached yet in one batch.
+func (s *EmbeddingToolSelector) toolVectors(ctx context.Context, tools map[string]tool.Tool, names []string) (map[string][]float32, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var missing []string
+	for _, name := range names {
+		if _, ok := s.vectors[toolText(tools[name])]; !ok {
+			missing = append(missing, toolText(tools[name]))
+		}
+	}
+
+	if len(missing) > 0 {
+		embedded, err := s.embedder.Embed(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for i, text := range missing {
+			s.vectors[text] = embedded[i]
+		}
+	}
+
+	vectors := make(map[string][]float32, len(names))
+	for _, name := range names {
+		vectors[name] = s.vectors[toolText(tools[name])]
+	}
+	return vectors, nil
+}
+
+func toolText(t tool.Tool) string {
+	return t.Name() + ": " + t.Description()
+}
@@ -0,0 +1,47 @@
+package gothought
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+)
+
+type countingEmbedder struct {
+	fakeEmbedder
+	texts int
+}
+
+func (c *countingEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	c.texts += len(texts)
+	return c.fakeEmbedder.Embed(ctx, texts)
+}
+
+func TestEmbeddingToolSelector(t *testing.T) {
+	embedder := &countingEmbedder{fakeEmbedder: fakeEmbedder{vectors: map[string][]float32{
+		"weather: fake tool":              {1, 0, 0},
+		"forecast: fake tool":             {0.9, 0.1, 0},
+		"stock_price: fake tool":          {0, 1, 0},
+		"translate: fake tool":            {0, 0, 1},
+		"help: fake tool":                 {0, 0.5, 0.5},
+		"Will it rain in Seoul tomorrow?": {0.95, 0.05, 0},
+		"How is AAPL doing?":              {0.1, 0.9, 0},
+	}}}
+	provider := &fakeProvider{responses: []Message{{Message: "ok"}}}
+	model := NewLanguageModel(provider, WithToolSelector(NewEmbeddingToolSelector(embedder, 2, "help")))
+	for _, name := range []string{"weather", "forecast", "stock_price", "translate", "help"} {
+		model.AddTool(&fakeTool{name: name})
+	}
+
+	_, err := model.HumanPrompt("Will it rain in Seoul tomorrow?").Q(context.TODO())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"weather", "forecast", "help"}, lo.Keys(provider.tools))
+
+	_, err = model.HumanPrompt("How is AAPL doing?").Q(context.TODO())
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"stock_price", "forecast", "help"}, lo.Keys(provider.tools))
+
+	// tool embeddings are cached: four tools and two queries
+	require.Equal(t, 6, embedder.texts)
+}
@@ -0,0 +1,210 @@
+package vectorstore
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Filter selects documents by their metadata. Every key must match for a document
+// to be selected. A key maps either to a plain value, which must be equal to the
+// metadata value, or to an operator object such as {"$gte": 2020, "$lt": 2024}.
+//
+// Supported operators are $eq, $ne, $gt, $gte, $lt, $lte, $in and $nin.
+// The special keys "$and" and "$or" take a list of nested filters.
+//
+// Filters are plain maps so they can be decoded directly from JSON, for example
+// from tool call arguments.
+type Filter map[string]any
+
+// Match reports whether metadata satisfies the filter.
+func (f Filter) Match(metadata map[string]any) (bool, error) {
+	for key, cond := range f {
+		var (
+			ok  bool
+			err error
+		)
+
+		switch key {
+		case "$and", "$or":
+			ok, err = matchLogical(key, cond, metadata)
+		default:
+			value, exists := metadata[key]
+			ok, err = matchCondition(value, exists, cond)
+		}
+
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func matchLogical(op string, cond any, metadata map[string]any) (bool, error) {
+	items, ok := cond.([]any)
+	if !ok {
+		if filters, isFilters := cond.([]Filter); isFilters {
+			for _, f := range filters {
+				items = append(items, f)
+			}
+		} else {
+			return false, fmt.Errorf("%s expects a list of filters", op)
+		}
+	}
+
+	for _, item := range items {
+		sub, err := toFilter(item)
+		if err != nil {
+			return false, err
+		}
+
+		matched, err := sub.Match(metadata)
+		if err != nil {
+			return false, err
+		}
+		if op == "$or" && matched {
+			return true, nil
+		}
+		if op == "$and" && !matched {
+			return false, nil
+		}
+	}
+	return op == "$and", nil
+}
+
+func toFilter(v any) (Filter, error) {
+	switch f := v.(type) {
+	case Filter:
+		return f, nil
+	case map[string]any:
+		return f, nil
+	}
+	return nil, fmt.Errorf("invalid nested filter %v", v)
+}
+
+func matchCondition(value any, exists bool, cond any) (bool, error) {
+	ops, isOps := cond.(map[string]any)
+	if f, isFilter := cond.(Filter); isFilter {
+		ops, isOps = f, true
+	}
+	if !isOps {
+		return exists && equal(value, cond), nil
+	}
+
+	for op, operand := range ops {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = exists && equal(value, operand)
+		case "$ne":
+			ok = !exists || !equal(value, operand)
+		case "$gt", "$gte", "$lt", "$lte":
+			if !exists {
+				return false, nil
+			}
+			c, comparable := compare(value, operand)
+			if !comparable {
+				return false, nil
+			}
+			ok = (op == "$gt" && c > 0) || (op == "$gte" && c >= 0) ||
+				(op == "$lt" && c < 0) || (op == "$lte" && c <= 0)
+		case "$in", "$nin":
+			list, isList := toList(operand)
+			if !isList {
+				return false, fmt.Errorf("%s expects a list", op)
+			}
+			found := exists && contains(list, value)
+			ok = found == (op == "$in")
+		default:
+			return false, fmt.Errorf("unknown filter operator %q", op)
+		}
+
+		if !ok {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// equal compares values treating all numeric kinds as float64, so that metadata decoded
+// from JSON matches filters built from Go integers. Slice values match when any element does.
+func equal(a, b any) bool {
+	if list, ok := toList(a); ok {
+		if _, bIsList := toList(b); !bIsList {
+			return contains(list, b)
+		}
+	}
+
+	fa, aNum := toFloat(a)
+	fb, bNum := toFloat(b)
+	if aNum && bNum {
+		return fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func contains(list []any, v any) bool {
+	for _, item := range list {
+		if equal(v, item) {
+			return true
+		}
+	}
+	return false
+}
+
+func compare(a, b any) (int, bool) {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case fa < fb:
+			return -1, true
+		case fa > fb:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	sa, aStr := a.(string)
+	sb, bStr := b.(string)
+	if !aStr || !bStr {
+		return 0, false
+	}
+	switch {
+	case sa < sb:
+		return -1, true
+	case sa > sb:
+		return 1, true
+	}
+	return 0, true
+}
+
+func toFloat(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+func toList(v any) ([]any, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
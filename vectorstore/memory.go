@@ -0,0 +1,207 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Memory is a VectorStore that keeps every document in memory and searches by
+// brute-force comparison. It is safe for concurrent use and can be snapshotted
+// to disk with Save and restored with Load.
+type Memory struct {
+	mu     sync.RWMutex
+	metric Metric
+	docs   map[string]Document
+}
+
+var _ VectorStore = (*Memory)(nil)
+
+// NewMemory creates an empty in-memory store comparing vectors with the given metric.
+// An empty metric defaults to Cosine.
+func NewMemory(metric Metric) *Memory {
+	if metric == "" {
+		metric = Cosine
+	}
+	return &Memory{metric: metric, docs: map[string]Document{}}
+}
+
+// Upsert inserts or replaces the documents. Every document must have an ID and a vector.
+func (m *Memory) Upsert(ctx context.Context, docs ...Document) error {
+	for _, doc := range docs {
+		if doc.ID == "" {
+			return errors.New("document id is required")
+		}
+		if len(doc.Vector) == 0 {
+			return errors.New("document vector is required")
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, doc := range docs {
+		m.docs[doc.ID] = copyDocument(doc)
+	}
+	return nil
+}
+
+// Delete removes the documents with the given IDs.
+func (m *Memory) Delete(ctx context.Context, ids ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range ids {
+		delete(m.docs, id)
+	}
+	return nil
+}
+
+// Get returns a copy of the document with the given ID.
+func (m *Memory) Get(id string) (Document, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	doc, ok := m.docs[id]
+	if !ok {
+		return Document{}, false
+	}
+	return copyDocument(doc), true
+}
+
+// Len returns the number of stored documents.
+func (m *Memory) Len() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.docs)
+}
+
+// Search scores every document matching the filter against vector and returns the
+// best TopK results at or above the score threshold.
+func (m *Memory) Search(ctx context.Context, vector []float32, options ...SearchOption) ([]Result, error) {
+	opts := NewSearchOptions(options...)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := []Result{}
+	for _, doc := range m.docs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if opts.Filter != nil {
+			ok, err := opts.Filter.Match(doc.Metadata)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		score, err := m.metric.Score(vector, doc.Vector)
+		if err != nil {
+			return nil, err
+		}
+		if opts.ScoreThreshold != nil && score < *opts.ScoreThreshold {
+			continue
+		}
+		results = append(results, Result{Document: doc, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+
+	if opts.TopK > 0 && len(results) > opts.TopK {
+		results = results[:opts.TopK]
+	}
+	// callers must not be able to modify the stored documents
+	for i := range results {
+		results[i].Document = copyDocument(results[i].Document)
+	}
+	return results, nil
+}
+
+// copyDocument returns doc with its own copy of the vector and metadata.
+func copyDocument(doc Document) Document {
+	doc.Vector = append([]float32(nil), doc.Vector...)
+	doc.Metadata = maps.Clone(doc.Metadata)
+	return doc
+}
+
+type memorySnapshot struct {
+	Metric    Metric     `json:"metric"`
+	Documents []Document `json:"documents"`
+}
+
+// Save writes a JSON snapshot of the store to w.
+func (m *Memory) Save(w io.Writer) error {
+	m.mu.RLock()
+	snapshot := memorySnapshot{Metric: m.metric, Documents: make([]Document, 0, len(m.docs))}
+	for _, doc := range m.docs {
+		snapshot.Documents = append(snapshot.Documents, doc)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(snapshot.Documents, func(i, j int) bool {
+		return snapshot.Documents[i].ID < snapshot.Documents[j].ID
+	})
+	return json.NewEncoder(w).Encode(snapshot)
+}
+
+// Load replaces the contents and metric of the store with a snapshot read from r.
+func (m *Memory) Load(r io.Reader) error {
+	var snapshot memorySnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return err
+	}
+
+	docs := make(map[string]Document, len(snapshot.Documents))
+	for _, doc := range snapshot.Documents {
+		docs[doc.ID] = doc
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if snapshot.Metric != "" {
+		m.metric = snapshot.Metric
+	}
+	m.docs = docs
+	return nil
+}
+
+// SaveFile atomically writes a snapshot of the store to path.
+func (m *Memory) SaveFile(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := m.Save(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// LoadFile restores the store from a snapshot previously written by SaveFile.
+func (m *Memory) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return m.Load(f)
+}
@@ -0,0 +1,127 @@
+package vectorstore
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testDocuments() []Document {
+	return []Document{
+		{ID: "a", Content: "go", Vector: []float32{1, 0}, Metadata: map[string]any{"lang": "go", "year": 2023}},
+		{ID: "b", Content: "rust", Vector: []float32{0.8, 0.6}, Metadata: map[string]any{"lang": "rust", "year": 2021}},
+		{ID: "c", Content: "python", Vector: []float32{0, 1}, Metadata: map[string]any{"lang": "python", "year": 2019, "tags": []string{"ml"}}},
+	}
+}
+
+func ids(results []Result) []string {
+	out := make([]string, len(results))
+	for i, r := range results {
+		out[i] = r.ID
+	}
+	return out
+}
+
+func TestMemory_Search(t *testing.T) {
+	ctx := context.TODO()
+	store := NewMemory(Cosine)
+	require.NoError(t, store.Upsert(ctx, testDocuments()...))
+
+	results, err := store.Search(ctx, []float32{1, 0})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, ids(results))
+	require.InDelta(t, 1, results[0].Score, 1e-6)
+
+	results, err = store.Search(ctx, []float32{1, 0}, WithTopK(1))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a"}, ids(results))
+
+	results, err = store.Search(ctx, []float32{1, 0}, WithScoreThreshold(0.5))
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, ids(results))
+
+	require.NoError(t, store.Delete(ctx, "a"))
+	results, err = store.Search(ctx, []float32{1, 0})
+	require.NoError(t, err)
+	require.Equal(t, []string{"b", "c"}, ids(results))
+
+	_, err = store.Search(ctx, []float32{1, 0, 0})
+	require.Error(t, err)
+}
+
+func TestMemory_ResultsAreCopies(t *testing.T) {
+	ctx := context.TODO()
+	store := NewMemory(Cosine)
+	docs := testDocuments()
+	require.NoError(t, store.Upsert(ctx, docs...))
+	docs[0].Metadata["lang"] = "changed"
+
+	results, err := store.Search(ctx, []float32{1, 0}, WithTopK(1))
+	require.NoError(t, err)
+	results[0].Vector[0] = -1
+	results[0].Metadata["lang"] = "changed"
+
+	doc, ok := store.Get("a")
+	require.True(t, ok)
+	require.Equal(t, []float32{1, 0}, doc.Vector)
+	require.Equal(t, "go", doc.Metadata["lang"])
+	doc.Metadata["lang"] = "changed"
+
+	results, err = store.Search(ctx, []float32{1, 0}, WithTopK(1))
+	require.NoError(t, err)
+	require.Equal(t, "a", results[0].ID)
+	require.Equal(t, "go", results[0].Metadata["lang"])
+}
+
+func TestMemory_SearchFilter(t *testing.T) {
+	ctx := context.TODO()
+	store := NewMemory(Euclidean)
+	require.NoError(t, store.Upsert(ctx, testDocuments()...))
+
+	cases := []struct {
+		filter string
+		want   []string
+	}{
+		{`{"lang": "go"}`, []string{"a"}},
+		{`{"year": {"$gte": 2021}}`, []string{"a", "b"}},
+		{`{"lang": {"$in": ["go", "python"]}, "year": {"$lt": 2020}}`, []string{"c"}},
+		{`{"lang": {"$nin": ["go"]}}`, []string{"b", "c"}},
+		{`{"tags": "ml"}`, []string{"c"}},
+		{`{"$or": [{"lang": "go"}, {"year": 2019}]}`, []string{"a", "c"}},
+		{`{"missing": {"$ne": 1}}`, []string{"a", "b", "c"}},
+	}
+
+	for _, c := range cases {
+		var filter Filter
+		require.NoError(t, json.Unmarshal([]byte(c.filter), &filter))
+
+		results, err := store.Search(ctx, []float32{1, 0}, WithFilter(filter), WithTopK(10))
+		require.NoError(t, err, c.filter)
+		require.ElementsMatch(t, c.want, ids(results), c.filter)
+	}
+
+	_, err := store.Search(ctx, []float32{1, 0}, WithFilter(Filter{"year": map[string]any{"$regex": "x"}}))
+	require.Error(t, err)
+}
+
+func TestMemory_Snapshot(t *testing.T) {
+	ctx := context.TODO()
+	store := NewMemory(DotProduct)
+	require.NoError(t, store.Upsert(ctx, testDocuments()...))
+
+	path := filepath.Join(t.TempDir(), "store.json")
+	require.NoError(t, store.SaveFile(path))
+
+	restored := NewMemory(Cosine)
+	require.NoError(t, restored.LoadFile(path))
+	require.Equal(t, 3, restored.Len())
+	require.Equal(t, DotProduct, restored.metric)
+
+	doc, ok := restored.Get("b")
+	require.True(t, ok)
+	require.Equal(t, "rust", doc.Content)
+	require.Equal(t, []float32{0.8, 0.6}, doc.Vector)
+}
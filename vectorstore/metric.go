@@ -0,0 +1,46 @@
+package vectorstore
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gobenpark/gothought/embedding"
+)
+
+// Metric defines how two vectors are compared.
+type Metric string
+
+const (
+	// Cosine scores by the cosine of the angle between vectors, in [-1, 1].
+	Cosine Metric = "cosine"
+	// DotProduct scores by the inner product, which equals Cosine for normalised vectors.
+	DotProduct Metric = "dot"
+	// Euclidean scores by 1 / (1 + L2 distance), in (0, 1].
+	Euclidean Metric = "l2"
+)
+
+// Score returns the similarity of a and b under the metric. Higher is more similar.
+func (m Metric) Score(a, b []float32) (float32, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("vector dimension mismatch: %d != %d", len(a), len(b))
+	}
+
+	switch m {
+	case Cosine:
+		return embedding.Cosine(a, b), nil
+	case DotProduct:
+		var dot float64
+		for i := range a {
+			dot += float64(a[i]) * float64(b[i])
+		}
+		return float32(dot), nil
+	case Euclidean:
+		var sum float64
+		for i := range a {
+			d := float64(a[i]) - float64(b[i])
+			sum += d * d
+		}
+		return float32(1 / (1 + math.Sqrt(sum))), nil
+	}
+	return 0, fmt.Errorf("unknown metric %q", m)
+}
@@ -0,0 +1,69 @@
+package vectorstore
+
+import "context"
+
+// Document is a piece of content stored together with its embedding vector.
+type Document struct {
+	ID       string         `json:"id"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	Vector   []float32      `json:"vector"`
+}
+
+// Result is a document returned by a similarity search together with its score.
+// Higher scores always mean more similar, regardless of the metric in use.
+type Result struct {
+	Document
+	Score float32 `json:"score"`
+}
+
+// VectorStore persists documents and finds the ones most similar to a query vector.
+type VectorStore interface {
+	// Upsert inserts the documents, replacing any existing document with the same ID.
+	Upsert(ctx context.Context, docs ...Document) error
+
+	// Delete removes the documents with the given IDs. Unknown IDs are ignored.
+	Delete(ctx context.Context, ids ...string) error
+
+	// Search returns the documents most similar to vector, best match first.
+	Search(ctx context.Context, vector []float32, options ...SearchOption) ([]Result, error)
+}
+
+// SearchOptions controls the results of VectorStore.Search.
+type SearchOptions struct {
+	TopK           int
+	ScoreThreshold *float32
+	Filter         Filter
+}
+
+type SearchOption func(o *SearchOptions)
+
+// WithTopK limits the number of returned results. The default is 4.
+func WithTopK(k int) SearchOption {
+	return func(o *SearchOptions) {
+		o.TopK = k
+	}
+}
+
+// WithScoreThreshold drops results scoring below the threshold
+func WithScoreThreshold(threshold float32) SearchOption {
+	return func(o *SearchOptions) {
+		o.ScoreThreshold = &threshold
+	}
+}
+
+// WithFilter only returns documents whose metadata matches the filter
+func WithFilter(filter Filter) SearchOption {
+	return func(o *SearchOptions) {
+		o.Filter = filter
+	}
+}
+
+// NewSearchOptions applies options on top of the defaults.
+func NewSearchOptions(options ...SearchOption) SearchOptions {
+	o := SearchOptions{TopK: 4}
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}